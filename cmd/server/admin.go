@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+)
+
+// PurgeCache implements the MCPServiceServer interface
+func (s *server) PurgeCache(ctx context.Context, req *proto.PurgeCacheRequest) (*proto.PurgeCacheResponse, error) {
+	if s.cache == nil {
+		return &proto.PurgeCacheResponse{Error: "response cache is disabled"}, nil
+	}
+
+	removed, err := s.cache.Purge(ctx, req.ModelId)
+	if err != nil {
+		return &proto.PurgeCacheResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.PurgeCacheResponse{
+		Removed: removed,
+	}, nil
+}
+
+// SetLogLevel implements the MCPServiceServer interface
+func (s *server) SetLogLevel(ctx context.Context, req *proto.LogLevelRequest) (*proto.LogLevelResponse, error) {
+	previous := s.logger.Level()
+	if req.Level != "" {
+		if err := s.logger.SetLevel(req.Level); err != nil {
+			return &proto.LogLevelResponse{Level: previous, Error: err.Error()}, nil
+		}
+		slog.InfoContext(ctx, "Log level changed", "level", s.logger.Level(), "previous", previous)
+	}
+
+	return &proto.LogLevelResponse{
+		Level:    s.logger.Level(),
+		Previous: previous,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+)
+
+// maxBatchSize bounds the inputs of a batch
+const maxBatchSize = 10000
+
+// ExecuteBatch implements the MCPServiceServer interface. The batch's
+// executions are queued like any other, with batch priority unless the
+// request sets one, so they share the workers fairly and respect the
+// provider concurrency limits.
+func (s *server) ExecuteBatch(ctx context.Context, req *proto.BatchRequest) (*proto.BatchResponse, error) {
+	if req.Request == nil {
+		return &proto.BatchResponse{Error: "batch request is required"}, nil
+	}
+	params, err := structParams(req.Request.Parameters, req.Request.Params)
+	if err != nil {
+		return &proto.BatchResponse{Error: err.Error()}, nil
+	}
+
+	inputs := req.Inputs
+	var dataIDs []string
+	if req.DataType != "" || len(req.DataFilter) > 0 {
+		if len(inputs) > 0 {
+			return &proto.BatchResponse{Error: "batch takes either inputs or a data filter"}, nil
+		}
+		items, err := s.dataRepo.Find(ctx, req.DataType, req.DataFilter, maxBatchSize+1)
+		if err != nil {
+			return &proto.BatchResponse{Error: err.Error()}, nil
+		}
+		for _, item := range items {
+			inputs = append(inputs, item.Content)
+			dataIDs = append(dataIDs, item.ID.Hex())
+		}
+	}
+	if len(inputs) == 0 {
+		return &proto.BatchResponse{Error: "batch has no inputs"}, nil
+	}
+	if len(inputs) > maxBatchSize {
+		return &proto.BatchResponse{Error: fmt.Sprintf("batch has more than %d inputs", maxBatchSize)}, nil
+	}
+
+	// Resolve the request once; each input only needs its context fitted
+	plan, err := s.planExecution(ctx, req.Request, protocol.PriorityBatch)
+	if err != nil {
+		return &proto.BatchResponse{Error: err.Error()}, nil
+	}
+	executions := make([]*protocol.Execution, len(inputs))
+	for i, input := range inputs {
+		execution, err := plan.execution(input)
+		if err != nil {
+			return &proto.BatchResponse{Error: fmt.Sprintf("input %d: %v", i, err)}, nil
+		}
+		if dataIDs != nil {
+			execution.Metadata["data_id"] = dataIDs[i]
+		}
+		executions[i] = execution
+	}
+
+	first := executions[0]
+	batch := &protocol.Batch{
+		ProtocolID: first.ProtocolID,
+		Type:       first.Type,
+		ModelID:    first.ModelID,
+		ContextID:  first.ContextID,
+		Parameters: params,
+		Priority:   first.Priority,
+		Tenant:     first.Tenant,
+	}
+	if err := s.protocolRepo.CreateBatch(ctx, batch, executions); err != nil {
+		return &proto.BatchResponse{Error: err.Error()}, nil
+	}
+	for _, execution := range executions {
+		s.engine.Submit(execution)
+	}
+
+	return &proto.BatchResponse{
+		Id:    batch.ID.Hex(),
+		Total: int32(batch.Total),
+	}, nil
+}
+
+// GetBatchStatus implements the MCPServiceServer interface
+func (s *server) GetBatchStatus(ctx context.Context, req *proto.BatchStatusRequest) (*proto.BatchStatus, error) {
+	batch, err := s.protocolRepo.GetBatch(ctx, req.Id)
+	if err != nil {
+		return &proto.BatchStatus{Error: err.Error()}, nil
+	}
+
+	progress, err := s.protocolRepo.GetBatchProgress(ctx, batch)
+	if err != nil {
+		return &proto.BatchStatus{Error: err.Error()}, nil
+	}
+
+	return &proto.BatchStatus{
+		Id:        batch.ID.Hex(),
+		Status:    progress.Status(),
+		Total:     progress.Total,
+		Pending:   progress.Pending,
+		Running:   progress.Running,
+		Completed: progress.Completed,
+		Failed:    progress.Failed,
+		Cancelled: progress.Cancelled,
+		Progress:  progress.Percent(),
+	}, nil
+}
+
+// ExportBatch implements the MCPServiceServer interface
+func (s *server) ExportBatch(req *proto.BatchStatusRequest, stream proto.MCPService_ExportBatchServer) error {
+	batch, err := s.protocolRepo.GetBatch(stream.Context(), req.Id)
+	if err != nil {
+		return err
+	}
+
+	return s.protocolRepo.EachBatchExecution(stream.Context(), batch.ID.Hex(), func(e *protocol.Execution) error {
+		return stream.Send(executionToProto(e))
+	})
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListExecutions implements the MCPServiceServer interface
+func (s *server) ListExecutions(ctx context.Context, req *proto.ListExecutionsRequest) (*proto.ExecutionList, error) {
+	if req.PageSize == 0 {
+		req.PageSize = 10 // Default page size
+	}
+
+	filter := protocol.ExecutionFilter{
+		ContextID:  req.ContextId,
+		ProtocolID: req.ProtocolId,
+		Status:     req.Status,
+		Tenant:     req.Tenant,
+	}
+	if req.ModelId != "" {
+		modelID, err := s.modelFilter(ctx, req.ModelId)
+		if err != nil {
+			return &proto.ExecutionList{Error: err.Error()}, nil
+		}
+		filter.ModelID = modelID
+	}
+	if req.CreatedAfter != nil {
+		filter.CreatedAfter = req.CreatedAfter.AsTime()
+	}
+	if req.CreatedBefore != nil {
+		filter.CreatedBefore = req.CreatedBefore.AsTime()
+	}
+
+	executions, nextPageToken, err := s.protocolRepo.ListExecutions(ctx, filter, req.PageSize, req.PageToken)
+	if err != nil {
+		return &proto.ExecutionList{Error: err.Error()}, nil
+	}
+
+	var protoExecutions []*proto.Execution
+	for _, e := range executions {
+		protoExecutions = append(protoExecutions, executionToProto(e))
+	}
+
+	return &proto.ExecutionList{
+		Executions:    protoExecutions,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// GetExecution implements the MCPServiceServer interface
+func (s *server) GetExecution(ctx context.Context, req *proto.ProtocolRequest) (*proto.ExecutionResponse, error) {
+	execution, err := s.protocolRepo.GetExecutionStatus(ctx, req.Id)
+	if err != nil {
+		return &proto.ExecutionResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ExecutionResponse{
+		Execution: executionToProto(execution),
+	}, nil
+}
+
+// GetQueueStats implements the MCPServiceServer interface
+func (s *server) GetQueueStats(ctx context.Context, req *proto.QueueStatsRequest) (*proto.QueueStats, error) {
+	depths, err := s.protocolRepo.QueueDepths(ctx, req.Tenant)
+	if err != nil {
+		return &proto.QueueStats{Error: err.Error()}, nil
+	}
+
+	stats := &proto.QueueStats{}
+	totals := make(map[string]*proto.QueueDepth)
+	for _, priority := range protocol.Priorities {
+		totals[priority] = &proto.QueueDepth{Priority: priority}
+		stats.Priorities = append(stats.Priorities, totals[priority])
+	}
+	for _, d := range depths {
+		stats.Queues = append(stats.Queues, &proto.QueueDepth{
+			Priority: d.Priority,
+			Tenant:   d.Tenant,
+			Pending:  d.Pending,
+			Running:  d.Running,
+		})
+		if total, ok := totals[d.Priority]; ok {
+			total.Pending += d.Pending
+			total.Running += d.Running
+		}
+	}
+
+	return stats, nil
+}
+
+// executionToProto converts a stored execution to its API representation
+func executionToProto(e *protocol.Execution) *proto.Execution {
+	return &proto.Execution{
+		Id:               e.ID.Hex(),
+		ProtocolId:       e.ProtocolID,
+		Type:             e.Type,
+		ModelId:          e.ModelID,
+		ContextId:        e.ContextID,
+		Input:            e.Input,
+		Output:           e.Result,
+		Status:           e.Status,
+		Error:            e.Error,
+		Parameters:       e.Metadata,
+		Steps:            stepStatusesToProto(e.Steps),
+		Attempts:         int32(len(e.Attempts)),
+		PromptTokens:     int32(e.Usage.PromptTokens),
+		CompletionTokens: int32(e.Usage.CompletionTokens),
+		CreatedAt:        timestampOrNil(e.CreatedAt),
+		StartedAt:        timestampOrNil(e.StartedAt),
+		FinishedAt:       timestampOrNil(e.FinishedAt),
+		DurationMs:       e.Duration().Milliseconds(),
+		Priority:         e.Priority,
+		Tenant:           e.Tenant,
+		BatchId:          e.BatchID,
+		BatchIndex:       int32(e.BatchIndex),
+		Cached:           e.Cached,
+		Cost:             e.Usage.Cost,
+		ApiKey:           e.APIKeyID,
+		ServedBy:         e.ServedBy,
+		ServedByVersion:  int32(e.ServedByVersion),
+		ModelVersion:     int32(e.ModelVersion),
+		ModelRef:         e.ModelRef,
+		Models:           pinsToProto(e.Models),
+	}
+}
+
+// pinsToProto lists the model versions pinned on an execution
+func pinsToProto(pins []protocol.ModelPin) []string {
+	var result []string
+	for _, pin := range pins {
+		result = append(result, pin.Ref+"="+pin.String())
+	}
+	return result
+}
+
+// statusToProto converts an execution to its status and progress
+func statusToProto(e *protocol.Execution) *proto.ProtocolStatus {
+	return &proto.ProtocolStatus{
+		Status:           e.Status,
+		Error:            e.Error,
+		Steps:            stepStatusesToProto(e.Steps),
+		Output:           e.Result,
+		Progress:         e.Progress(),
+		CurrentSteps:     e.Running,
+		StartedAt:        timestampOrNil(e.StartedAt),
+		FinishedAt:       timestampOrNil(e.FinishedAt),
+		Attempts:         int32(len(e.Attempts)),
+		PromptTokens:     int32(e.Usage.PromptTokens),
+		CompletionTokens: int32(e.Usage.CompletionTokens),
+		DurationMs:       e.Duration().Milliseconds(),
+		Cached:           e.Cached,
+		Cost:             e.Usage.Cost,
+	}
+}
+
+func stepStatusesToProto(steps []protocol.StepResult) []*proto.StepStatus {
+	var result []*proto.StepStatus
+	for _, step := range steps {
+		result = append(result, &proto.StepStatus{
+			Id:           step.ID,
+			Type:         step.Type,
+			Status:       step.Status,
+			Output:       step.Output,
+			Error:        step.Error,
+			ModelId:      step.ModelID,
+			ModelVersion: int32(step.ModelVersion),
+		})
+	}
+	return result
+}
+
+// timestampOrNil leaves unset times out of responses
+func timestampOrNil(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
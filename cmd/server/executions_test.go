@@ -0,0 +1,53 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+)
+
+func TestStatusToProto(t *testing.T) {
+	started := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	execution := &protocol.Execution{
+		Status:     protocol.StatusRunning,
+		Result:     "partial",
+		TotalSteps: 4,
+		Steps: []protocol.StepResult{
+			{ID: "render", Type: protocol.StepRender, Status: protocol.StatusCompleted},
+		},
+		Running:    []string{"summarize", "classify"},
+		StartedAt:  started,
+		FinishedAt: started.Add(2500 * time.Millisecond),
+		Attempts:   []protocol.Attempt{{StepID: "summarize", Number: 1}, {StepID: "summarize", Number: 2}},
+	}
+	execution.Usage.PromptTokens = 120
+	execution.Usage.CompletionTokens = 30
+
+	status := statusToProto(execution)
+	if status.Status != protocol.StatusRunning || status.Output != "partial" {
+		t.Errorf("status = %q, output = %q", status.Status, status.Output)
+	}
+	if status.Progress != 25 {
+		t.Errorf("progress = %v, want 25", status.Progress)
+	}
+	if !reflect.DeepEqual(status.CurrentSteps, []string{"summarize", "classify"}) {
+		t.Errorf("current steps = %v", status.CurrentSteps)
+	}
+	if len(status.Steps) != 1 || status.Steps[0].Id != "render" {
+		t.Errorf("steps = %v", status.Steps)
+	}
+	if !status.StartedAt.AsTime().Equal(started) || status.DurationMs != 2500 {
+		t.Errorf("started at %v, took %dms", status.StartedAt.AsTime(), status.DurationMs)
+	}
+	if status.Attempts != 2 || status.PromptTokens != 120 || status.CompletionTokens != 30 {
+		t.Errorf("attempts = %d, tokens = %d + %d", status.Attempts, status.PromptTokens, status.CompletionTokens)
+	}
+
+	// Executions that have not started report no times
+	pending := statusToProto(&protocol.Execution{Status: protocol.StatusPending})
+	if pending.StartedAt != nil || pending.FinishedAt != nil || pending.DurationMs != 0 {
+		t.Errorf("pending execution reports times: %v", pending)
+	}
+}
@@ -2,33 +2,102 @@ package main
 
 import (
 	"context"
-	"log"
+	"flag"
+	"fmt"
+	"log/slog"
 	"net"
 	"os"
-	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/DavutcanJ/mongo-mcp-server/internal/cache"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/changes"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/config"
 	"github.com/DavutcanJ/mongo-mcp-server/internal/cursor"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/engine"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/healthcheck"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/lifecycle"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/logging"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/metrics"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/prober"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/provider"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/scheduler"
 	svcContext "github.com/DavutcanJ/mongo-mcp-server/internal/service/context"
 	"github.com/DavutcanJ/mongo-mcp-server/internal/service/data"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/health"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/lease"
 	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
 	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/schedule"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/secret"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/tokenizer"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/tracing"
 	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	log.Println("Starting MCP Server...")
+	configPath := flag.String("config", "configs/mcp-server.json", "path to the server configuration")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		logging.Fatal("Failed to load config", "path", *configPath, "error", err)
+	}
+
+	// Structured logs, at the configured level and with content redacted
+	logger, err := logging.Setup(logging.Config{
+		Level:  cfg.Logging.Level,
+		Format: cfg.Logging.Format,
+		Redact: cfg.Logging.Redact,
+	}, os.Stderr)
+	if err != nil {
+		logging.Fatal("Invalid logging config", "error", err)
+	}
+	slog.Info("Starting MCP Server", "config", *configPath)
+
+	// Prometheus metrics
+	var serverMetrics *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		serverMetrics = metrics.New()
+	}
+
+	// Tracing, exported to OTLP or stdout as configured
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logging.Fatal("Failed to set up tracing", "error", err)
+	}
 
 	// MongoDB bağlantısı
-	log.Println("Connecting to MongoDB...")
-	mongoURI := "mongodb://localhost:27017"
-	client, err := mongo.NewClient(options.Client().ApplyURI(mongoURI))
+	slog.Info("Connecting to MongoDB")
+	mongoURI := cfg.Database.URL
+	clientOpts := options.Client().ApplyURI(mongoURI)
+	var monitor *event.CommandMonitor
+	if serverMetrics != nil {
+		monitor = serverMetrics.CommandMonitor()
+	}
+	if cfg.Tracing.Exporter != tracing.ExporterNone {
+		monitor = tracing.CommandMonitor(monitor)
+	}
+	if monitor != nil {
+		clientOpts.SetMonitor(monitor)
+	}
+	client, err := mongo.NewClient(clientOpts)
 	if err != nil {
-		log.Fatalf("MongoDB client oluşturulamadı: %v", err)
+		logging.Fatal("Failed to create MongoDB client", "error", err)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -36,43 +105,111 @@ func main() {
 
 	err = client.Connect(ctx)
 	if err != nil {
-		log.Fatalf("MongoDB'ye bağlanılamadı: %v", err)
+		logging.Fatal("Failed to connect to MongoDB", "error", err)
 	}
-	defer client.Disconnect(ctx)
 
 	// Ping MongoDB to verify connection
 	err = client.Ping(ctx, nil)
 	if err != nil {
-		log.Fatalf("MongoDB connection test failed: %v", err)
+		logging.Fatal("MongoDB connection test failed", "error", err)
 	}
-	log.Println("Successfully connected to MongoDB")
+	slog.Info("Connected to MongoDB")
 
 	// Veritabanı seçimi
-	db := client.Database("mcp_db")
-	log.Printf("Using database: %s", db.Name())
+	db := client.Database(cfg.Database.Name)
+	slog.Info("Using database", "database", db.Name())
+
+	// Tokenizers
+	if err := tokenizer.LoadFromEnv(); err != nil {
+		logging.Fatal("Failed to load tokenizer", "error", err)
+	}
+	if !tokenizer.Registered(tokenizer.CL100KBase) {
+		slog.Warn("No cl100k_base rank file set, GPT models are counted by estimate", "env", tokenizer.RanksFileEnv)
+	}
+
+	// Parameter schemas of the configured model types
+	for modelType, schema := range cfg.ModelSchemas {
+		if err := model.RegisterSchema(modelType, schema); err != nil {
+			logging.Fatal("Invalid model schema", "type", modelType, "error", err)
+		}
+	}
 
 	// Repository'lerin oluşturulması
-	log.Println("Initializing repositories...")
+	slog.Info("Initializing repositories")
 	modelRepo := model.NewModelRepository(db)
 	contextRepo := svcContext.NewContextRepository(db)
 	protocolRepo := protocol.NewProtocolRepository(db)
 	dataRepo := data.NewDataRepository(db)
+	scheduleRepo := schedule.NewScheduleRepository(db)
+	healthRepo := health.NewHealthRepository(db)
+	leaseRepo := lease.NewLeaseRepository(db)
+
+	// Secrets, encrypted with the key from the environment or key file
+	secretCipher, err := secret.LoadCipher(cfg.Secrets.KeyFile)
+	if err != nil {
+		logging.Fatal("Failed to load secrets key", "error", err)
+	}
+	if secretCipher == nil {
+		slog.Warn("No secrets key set, secrets are disabled", "env", secret.KeyEnv)
+	}
+	secretRepo := secret.NewSecretRepository(db, secretCipher)
+	if err := modelRepo.EnsureIndexes(ctx); err != nil {
+		logging.Fatal("Failed to create model indexes", "error", err)
+	}
+
+	// Response cache
+	var responseCache *cache.Cache
+	if cfg.Cache.Enabled {
+		responseCache = cache.NewCache(db, cfg.Cache.MemoryEntries)
+		if err := responseCache.EnsureIndexes(ctx); err != nil {
+			logging.Fatal("Failed to create cache indexes", "error", err)
+		}
+	}
+
+	// Model health checks, kept for the configured retention
+	retention := time.Duration(cfg.ModelHealth.RetentionHours) * time.Hour
+	if retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+	if err := healthRepo.EnsureIndexes(ctx, retention); err != nil {
+		logging.Fatal("Failed to create model health indexes", "error", err)
+	}
+
+	// Model providers, and the prober whose unhealthy models routers skip
+	providers := provider.DefaultRegistry()
+	providers.SetSecrets(secretRepo)
+	var modelProber *prober.Prober
 
 	// Cursor entegrasyonu
-	log.Println("Initializing cursor integration...")
+	slog.Info("Initializing cursor integration")
 	cursorIntegration, err := cursor.NewIntegration("localhost:50051")
 	if err != nil {
-		log.Fatalf("Cursor entegrasyonu başlatılamadı: %v", err)
+		logging.Fatal("Failed to initialize cursor integration", "error", err)
 	}
 
 	// gRPC sunucusu oluşturma
-	log.Println("Starting gRPC server...")
-	lis, err := net.Listen("tcp", ":50051")
+	slog.Info("Starting gRPC server")
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Connection.Port))
 	if err != nil {
-		log.Fatalf("gRPC sunucusu başlatılamadı: %v", err)
+		logging.Fatal("Failed to listen", "port", cfg.Connection.Port, "error", err)
+	}
+
+	unary := []grpc.UnaryServerInterceptor{logger.UnaryServerInterceptor(apiKeyID)}
+	stream := []grpc.StreamServerInterceptor{logger.StreamServerInterceptor(apiKeyID)}
+	if serverMetrics != nil {
+		unary = append(unary, serverMetrics.UnaryServerInterceptor())
+		stream = append(stream, serverMetrics.StreamServerInterceptor())
 	}
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
 
-	grpcServer := grpc.NewServer()
+	// Changes of models, contexts, data and executions, for subscribers
+	changeFeed := changes.NewFeed(db, changes.Config{
+		PollInterval: time.Duration(cfg.Changes.PollIntervalSeconds) * time.Second,
+	})
 
 	// Servisleri kaydetme
 	mcpServer := &server{
@@ -80,33 +217,147 @@ func main() {
 		contextRepo:  contextRepo,
 		protocolRepo: protocolRepo,
 		dataRepo:     dataRepo,
+		scheduleRepo: scheduleRepo,
+		healthRepo:   healthRepo,
+		secretRepo:   secretRepo,
+		logger:       logger,
+		cache:        responseCache,
+		changes:      changeFeed,
 		cursor:       cursorIntegration,
+		engine: engine.New(engine.Config{
+			Timeout:        time.Duration(cfg.Performance.TimeoutSeconds) * time.Second,
+			RetryAttempts:  cfg.Performance.RetryAttempts,
+			Workers:        cfg.Performance.MaxConcurrentRequests,
+			WorkerID:       cfg.Queue.WorkerID,
+			Lease:          time.Duration(cfg.Queue.LeaseSeconds) * time.Second,
+			PollInterval:   time.Duration(cfg.Queue.PollIntervalSeconds) * time.Second,
+			MaxClaims:      cfg.Queue.MaxClaims,
+			Weights:        cfg.Queue.Weights,
+			ProviderLimits: cfg.Performance.ProviderConcurrency,
+			Cache:          responseCache,
+			CacheTTL:       time.Duration(cfg.Cache.TTLSeconds) * time.Second,
+			Healthy: func(modelID string) bool {
+				return modelProber == nil || modelProber.Healthy(modelID)
+			},
+			Metrics: serverMetrics,
+		}, modelRepo, protocolRepo, dataRepo, providers),
 	}
 
+	if cfg.ModelHealth.Enabled {
+		modelProber = prober.New(prober.Config{
+			Owner:            mcpServer.engine.WorkerID(),
+			Interval:         time.Duration(cfg.ModelHealth.IntervalSeconds) * time.Second,
+			Timeout:          time.Duration(cfg.ModelHealth.TimeoutSeconds) * time.Second,
+			FailureThreshold: cfg.ModelHealth.FailureThreshold,
+			Concurrency:      cfg.ModelHealth.Concurrency,
+		}, modelRepo, healthRepo, leaseRepo, providers)
+	}
+
+	// Claim queued executions, including those left behind by stopped servers
+	engineCtx, stopEngine := context.WithCancel(context.Background())
+	mcpServer.engine.Start(engineCtx)
+
+	// Fire schedules while this server holds the scheduler leadership
+	schedules := scheduler.New(scheduler.Config{
+		Owner:    mcpServer.engine.WorkerID(),
+		Interval: time.Duration(cfg.Scheduler.IntervalSeconds) * time.Second,
+	}, scheduleRepo, leaseRepo, mcpServer.runSchedule)
+	schedules.Start(engineCtx)
+
+	// Probe models while this server holds the prober leadership
+	if modelProber != nil {
+		modelProber.Start(engineCtx)
+	}
+
+	// Serve metrics, including worker utilisation and the queue depth
+	stopMetrics := func(context.Context) error { return nil }
+	if serverMetrics != nil {
+		serverMetrics.RegisterWorkers(mcpServer.engine.Workers)
+		serverMetrics.RegisterQueue(func(ctx context.Context) ([]protocol.QueueDepth, error) {
+			return protocolRepo.QueueDepths(ctx, "")
+		})
+		stopMetrics = serverMetrics.Serve(cfg.Metrics.Address, cfg.Metrics.Path)
+	}
+
+	// Report the health of MongoDB, the workers and the providers
+	checker := healthcheck.New(healthcheck.Config{
+		Interval: time.Duration(cfg.HealthCheck.IntervalSeconds) * time.Second,
+		Timeout:  time.Duration(cfg.HealthCheck.TimeoutSeconds) * time.Second,
+		Services: []string{proto.MCPService_ServiceDesc.ServiceName},
+	})
+	checker.Add(healthcheck.MongoDB, func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	})
+	checker.Add(healthcheck.Workers, func(ctx context.Context) error {
+		return mcpServer.engine.Check()
+	})
+	checker.Add(healthcheck.Providers, func(ctx context.Context) error {
+		return checkProviders(ctx, healthRepo)
+	})
+	checker.Start(engineCtx)
+
 	// gRPC servisini kaydet
 	proto.RegisterMCPServiceServer(grpcServer, mcpServer)
-
-	// Graceful shutdown için sinyal yakalama
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	healthpb.RegisterHealthServer(grpcServer, checker.Server())
+	reflection.Register(grpcServer)
 
 	// Start server
-	log.Printf("MCP Server is running on %s", lis.Addr().String())
-	log.Println("Use Ctrl+C to stop the server")
+	slog.Info("MCP Server is running", "address", lis.Addr().String())
 
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalf("gRPC sunucusu başlatılamadı: %v", err)
+			logging.Fatal("gRPC server failed", "error", err)
 		}
 	}()
 
-	// Wait for shutdown signal
-	sig := <-sigChan
-	log.Printf("Received signal %v, initiating graceful shutdown...", sig)
+	// Shut down in order: report NOT_SERVING, stop accepting RPCs while
+	// the running ones and executions finish, then stop background work,
+	// flush telemetry and disconnect from MongoDB once nothing uses it
+	shutdown := lifecycle.New(lifecycle.Config{
+		GracePeriod:  time.Duration(cfg.Shutdown.GracePeriodSeconds) * time.Second,
+		CloseTimeout: time.Duration(cfg.Shutdown.CloseTimeoutSeconds) * time.Second,
+	})
+	shutdown.OnStop("health", checker.Shutdown)
+	shutdown.OnStop("subscriptions", mcpServer.changes.Close)
+	shutdown.OnDrain("grpc", func(ctx context.Context) error {
+		return stopGRPC(ctx, grpcServer)
+	})
+	shutdown.OnDrain("executions", mcpServer.engine.Drain)
+	shutdown.OnClose("background", func(ctx context.Context) error {
+		stopEngine()
+		schedules.Wait()
+		if modelProber != nil {
+			modelProber.Wait()
+		}
+		return nil
+	})
+	shutdown.OnClose("metrics", stopMetrics)
+	shutdown.OnClose("tracing", shutdownTracing)
+	shutdown.OnClose("mongodb", client.Disconnect)
 
-	// Graceful shutdown
-	grpcServer.GracefulStop()
-	log.Println("Server stopped gracefully")
+	sig := shutdown.Wait(syscall.SIGINT, syscall.SIGTERM)
+	slog.Info("Shutting down", "signal", sig.String())
+	shutdown.Shutdown()
+	slog.Info("Server stopped gracefully")
+}
+
+// stopGRPC stops accepting RPCs and waits for the running ones to finish,
+// cancelling those still running when ctx is done
+func stopGRPC(ctx context.Context, grpcServer *grpc.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		grpcServer.Stop()
+		<-stopped
+		return ctx.Err()
+	}
 }
 
 // server implements the MCPServiceServer interface
@@ -116,74 +367,494 @@ type server struct {
 	contextRepo  *svcContext.ContextRepository
 	protocolRepo *protocol.ProtocolRepository
 	dataRepo     *data.DataRepository
+	scheduleRepo *schedule.ScheduleRepository
+	healthRepo   *health.HealthRepository
+	secretRepo   *secret.SecretRepository
+	logger       *logging.Logger
+	cache        *cache.Cache
+	changes      *changes.Feed
 	cursor       *cursor.Integration
+	engine       *engine.Engine
 }
 
 // CreateModel implements the MCPServiceServer interface
 func (s *server) CreateModel(ctx context.Context, req *proto.Model) (*proto.ModelResponse, error) {
+	params, err := structParams(req.Parameters, req.Params)
+	if err != nil {
+		return &proto.ModelResponse{Error: err.Error()}, nil
+	}
+
 	model := &model.Model{
 		Name:       req.Name,
 		Type:       req.Type,
-		Parameters: req.Parameters,
+		Parameters: params,
 	}
 
+	if err := s.checkSecret(ctx, params); err != nil {
+		return &proto.ModelResponse{Error: err.Error()}, nil
+	}
 	if err := s.modelRepo.Create(ctx, model); err != nil {
 		return &proto.ModelResponse{Error: err.Error()}, nil
 	}
 
 	return &proto.ModelResponse{
-		Model: &proto.Model{
-			Id:         model.ID.Hex(),
-			Name:       model.Name,
-			Type:       model.Type,
-			Parameters: model.Parameters,
-		},
+		Model: modelToProto(model),
 	}, nil
 }
 
 // CreateContext implements the MCPServiceServer interface
 func (s *server) CreateContext(ctx context.Context, req *proto.Context) (*proto.ContextResponse, error) {
-	log.Printf("Creating context with name: %s", req.Name)
-
 	// Initialize metadata if nil
 	if req.Metadata == nil {
 		req.Metadata = make(map[string]string)
 	}
 
+	for _, modelID := range req.ModelIds {
+		if _, err := s.modelRepo.Get(ctx, modelID); err != nil {
+			return &proto.ContextResponse{Error: fmt.Sprintf("model %s: %v", modelID, err)}, nil
+		}
+	}
+
 	context := &svcContext.Context{
 		Name:     req.Name,
 		Content:  req.Content, // Add content field
+		ModelIDs: req.ModelIds,
 		Metadata: req.Metadata,
 	}
 
 	if err := s.contextRepo.Create(ctx, context); err != nil {
-		log.Printf("Error creating context: %v", err)
 		return &proto.ContextResponse{Error: err.Error()}, nil
 	}
 
-	log.Printf("Context created successfully with ID: %s", context.ID.Hex())
+	slog.InfoContext(ctx, "Context created", "context_id", context.ID.Hex())
 	return &proto.ContextResponse{
-		Context: &proto.Context{
-			Id:       context.ID.Hex(),
-			Name:     context.Name,
-			Content:  context.Content, // Return content in response
-			Metadata: context.Metadata,
-		},
+		Context: contextToProto(context),
+	}, nil
+}
+
+// GetContext implements the MCPServiceServer interface
+func (s *server) GetContext(ctx context.Context, req *proto.ContextRequest) (*proto.ContextResponse, error) {
+	context, err := s.contextRepo.Get(ctx, req.Id)
+	if err != nil {
+		return &proto.ContextResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ContextResponse{
+		Context: contextToProto(context),
+	}, nil
+}
+
+// ListContexts implements the MCPServiceServer interface
+func (s *server) ListContexts(ctx context.Context, req *proto.ListRequest) (*proto.ContextList, error) {
+	if req.PageSize == 0 {
+		req.PageSize = 10 // Default page size
+	}
+
+	contexts, nextPageToken, err := s.contextRepo.List(ctx, req.PageSize, req.Filters["pageToken"])
+	if err != nil {
+		return &proto.ContextList{Error: err.Error()}, nil
+	}
+
+	return contextList(contexts, nextPageToken), nil
+}
+
+// LinkContextModel implements the MCPServiceServer interface
+func (s *server) LinkContextModel(ctx context.Context, req *proto.ContextModelRequest) (*proto.ContextResponse, error) {
+	if _, err := s.modelRepo.Get(ctx, req.ModelId); err != nil {
+		return &proto.ContextResponse{Error: fmt.Sprintf("model %s: %v", req.ModelId, err)}, nil
+	}
+
+	context, err := s.contextRepo.LinkModel(ctx, req.ContextId, req.ModelId)
+	if err != nil {
+		return &proto.ContextResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ContextResponse{
+		Context: contextToProto(context),
+	}, nil
+}
+
+// UnlinkContextModel implements the MCPServiceServer interface
+func (s *server) UnlinkContextModel(ctx context.Context, req *proto.ContextModelRequest) (*proto.ContextResponse, error) {
+	context, err := s.contextRepo.UnlinkModel(ctx, req.ContextId, req.ModelId)
+	if err != nil {
+		return &proto.ContextResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ContextResponse{
+		Context: contextToProto(context),
+	}, nil
+}
+
+// ListContextsForModel implements the MCPServiceServer interface
+func (s *server) ListContextsForModel(ctx context.Context, req *proto.ModelContextsRequest) (*proto.ContextList, error) {
+	if req.PageSize == 0 {
+		req.PageSize = 10 // Default page size
+	}
+
+	contexts, nextPageToken, err := s.contextRepo.ListByModel(ctx, req.ModelId, req.PageSize, req.PageToken)
+	if err != nil {
+		return &proto.ContextList{Error: err.Error()}, nil
+	}
+
+	return contextList(contexts, nextPageToken), nil
+}
+
+// contextToProto converts a stored context to its API representation
+func contextToProto(c *svcContext.Context) *proto.Context {
+	return &proto.Context{
+		Id:       c.ID.Hex(),
+		Name:     c.Name,
+		Content:  c.Content,
+		Metadata: c.Metadata,
+		ModelIds: c.ModelIDs,
+	}
+}
+
+// contextList builds a ContextList, passing the next page token in the
+// metadata of the first context like the other list operations
+func contextList(contexts []*svcContext.Context, nextPageToken string) *proto.ContextList {
+	var protoContexts []*proto.Context
+	for _, c := range contexts {
+		protoContexts = append(protoContexts, contextToProto(c))
+	}
+
+	if nextPageToken != "" && len(protoContexts) > 0 {
+		if protoContexts[0].Metadata == nil {
+			protoContexts[0].Metadata = make(map[string]string)
+		}
+		protoContexts[0].Metadata["nextPageToken"] = nextPageToken
+	}
+
+	return &proto.ContextList{
+		Contexts: protoContexts,
+	}
+}
+
+// CreateProtocol implements the MCPServiceServer interface
+func (s *server) CreateProtocol(ctx context.Context, req *proto.ProtocolDefinition) (*proto.ProtocolDefinitionResponse, error) {
+	protocol := protocolFromProto(req)
+	if err := protocol.Validate(); err != nil {
+		return &proto.ProtocolDefinitionResponse{Error: err.Error()}, nil
+	}
+
+	if err := s.protocolRepo.Create(ctx, protocol); err != nil {
+		return &proto.ProtocolDefinitionResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ProtocolDefinitionResponse{
+		Protocol: protocolToProto(protocol),
+	}, nil
+}
+
+// GetProtocol implements the MCPServiceServer interface
+func (s *server) GetProtocol(ctx context.Context, req *proto.ProtocolDefinitionRequest) (*proto.ProtocolDefinitionResponse, error) {
+	protocol, err := s.protocolRepo.Get(ctx, req.Id)
+	if err != nil {
+		return &proto.ProtocolDefinitionResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ProtocolDefinitionResponse{
+		Protocol: protocolToProto(protocol),
+	}, nil
+}
+
+// ListProtocols implements the MCPServiceServer interface
+func (s *server) ListProtocols(ctx context.Context, req *proto.ListRequest) (*proto.ProtocolDefinitionList, error) {
+	if req.PageSize == 0 {
+		req.PageSize = 10 // Default page size
+	}
+
+	protocols, nextPageToken, err := s.protocolRepo.List(ctx, req.PageSize, req.Filters["pageToken"])
+	if err != nil {
+		return &proto.ProtocolDefinitionList{Error: err.Error()}, nil
+	}
+
+	var protoProtocols []*proto.ProtocolDefinition
+	for _, p := range protocols {
+		protoProtocols = append(protoProtocols, protocolToProto(p))
+	}
+
+	if nextPageToken != "" && len(protoProtocols) > 0 {
+		if protoProtocols[0].Parameters == nil {
+			protoProtocols[0].Parameters = make(map[string]string)
+		}
+		protoProtocols[0].Parameters["nextPageToken"] = nextPageToken
+	}
+
+	return &proto.ProtocolDefinitionList{
+		Protocols: protoProtocols,
+	}, nil
+}
+
+// UpdateProtocol implements the MCPServiceServer interface
+func (s *server) UpdateProtocol(ctx context.Context, req *proto.ProtocolDefinition) (*proto.ProtocolDefinitionResponse, error) {
+	existing, err := s.protocolRepo.Get(ctx, req.Id)
+	if err != nil {
+		return &proto.ProtocolDefinitionResponse{Error: err.Error()}, nil
+	}
+
+	protocol := protocolFromProto(req)
+	protocol.ID = existing.ID
+	protocol.CreatedAt = existing.CreatedAt
+	if err := protocol.Validate(); err != nil {
+		return &proto.ProtocolDefinitionResponse{Error: err.Error()}, nil
+	}
+
+	if err := s.protocolRepo.Update(ctx, protocol); err != nil {
+		return &proto.ProtocolDefinitionResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ProtocolDefinitionResponse{
+		Protocol: protocolToProto(protocol),
+	}, nil
+}
+
+// DeleteProtocol implements the MCPServiceServer interface
+func (s *server) DeleteProtocol(ctx context.Context, req *proto.ProtocolDefinitionRequest) (*proto.DeleteResponse, error) {
+	if err := s.protocolRepo.Delete(ctx, req.Id); err != nil {
+		return &proto.DeleteResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &proto.DeleteResponse{
+		Success: true,
 	}, nil
 }
 
+// protocolFromProto converts an API protocol definition to its stored form
+func protocolFromProto(p *proto.ProtocolDefinition) *protocol.Protocol {
+	return &protocol.Protocol{
+		Name:        p.Name,
+		Type:        p.Type,
+		Description: p.Description,
+		Steps:       stepsFromProto(p.Steps),
+		Parameters:  p.Parameters,
+	}
+}
+
+// protocolToProto converts a stored protocol definition to its API representation
+func protocolToProto(p *protocol.Protocol) *proto.ProtocolDefinition {
+	return &proto.ProtocolDefinition{
+		Id:          p.ID.Hex(),
+		Name:        p.Name,
+		Type:        p.Type,
+		Description: p.Description,
+		Steps:       stepsToProto(p.Steps),
+		Parameters:  p.Parameters,
+	}
+}
+
+func stepsFromProto(steps []*proto.Step) []protocol.Step {
+	var result []protocol.Step
+	for _, step := range steps {
+		result = append(result, protocol.Step{
+			ID:        step.Id,
+			Type:      step.Type,
+			DependsOn: step.DependsOn,
+			Config:    step.Config,
+		})
+	}
+	return result
+}
+
+func stepsToProto(steps []protocol.Step) []*proto.Step {
+	var result []*proto.Step
+	for _, step := range steps {
+		result = append(result, &proto.Step{
+			Id:        step.ID,
+			Type:      step.Type,
+			DependsOn: step.DependsOn,
+			Config:    step.Config,
+		})
+	}
+	return result
+}
+
 // ExecuteProtocol implements the MCPServiceServer interface
 func (s *server) ExecuteProtocol(ctx context.Context, req *proto.Protocol) (*proto.ProtocolResponse, error) {
-	execution, err := s.protocolRepo.ExecuteProtocol(ctx, req.ModelId, req.ContextId, req.Parameters)
+	execution, err := s.newExecution(ctx, req, req.Input, protocol.PriorityInteractive)
 	if err != nil {
 		return &proto.ProtocolResponse{Error: err.Error()}, nil
 	}
 
+	if err := s.protocolRepo.CreateExecution(ctx, execution); err != nil {
+		return &proto.ProtocolResponse{Error: err.Error()}, nil
+	}
+	s.engine.Submit(execution)
+
 	return &proto.ProtocolResponse{
 		Id: execution.ID.Hex(),
 	}, nil
 }
 
+// newExecution builds and validates the execution of req over input.
+// Executions without a priority get defaultPriority.
+func (s *server) newExecution(ctx context.Context, req *proto.Protocol, input, defaultPriority string) (*protocol.Execution, error) {
+	plan, err := s.planExecution(ctx, req, defaultPriority)
+	if err != nil {
+		return nil, err
+	}
+	return plan.execution(input)
+}
+
+// executionPlan is a request resolved once, from which the executions of
+// any number of inputs are made without querying the database again
+type executionPlan struct {
+	template *protocol.Execution // the execution without its input
+	model    *model.Model
+	context  *svcContext.Context
+}
+
+// planExecution resolves req's model, protocol and context, pins the
+// models its pipeline can call and validates its parameters. Executions without a priority get defaultPriority.
+func (s *server) planExecution(ctx context.Context, req *proto.Protocol, defaultPriority string) (*executionPlan, error) {
+	params, err := structParams(req.Parameters, req.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	execution := &protocol.Execution{
+		ProtocolID: req.ProtocolId,
+		Type:       req.Type,
+		ModelID:    req.ModelId,
+		ContextID:  req.ContextId,
+		Priority:   req.Priority,
+		Tenant:     req.Tenant,
+		APIKeyID:   apiKeyID(ctx),
+		Metadata:   params,
+	}
+	// Continue the caller's trace wherever the execution is claimed
+	execution.TraceContext = tracing.Inject(ctx)
+
+	if execution.Priority == "" {
+		execution.Priority = defaultPriority
+	}
+	if !protocol.ValidPriority(execution.Priority) {
+		return nil, fmt.Errorf("invalid priority %q, must be one of %v", execution.Priority, protocol.Priorities)
+	}
+	if execution.Tenant == "" {
+		execution.Tenant = protocol.DefaultTenant
+	}
+	m, err := s.resolveModel(ctx, execution)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyProtocol(ctx, execution); err != nil {
+		return nil, err
+	}
+	// Every execution of the plan calls the same model versions, even
+	// when claimed again after the references moved
+	if err := s.engine.PinModels(ctx, execution); err != nil {
+		return nil, err
+	}
+
+	// Parameters override the model's, so they must suit its type
+	modelType := ""
+	if m != nil {
+		modelType = m.Type
+	}
+	if err := model.SchemaFor(modelType).ValidateOverrides(execution.Metadata); err != nil {
+		return nil, err
+	}
+
+	c, err := s.linkedContext(ctx, execution, req.AllowUnlinked)
+	if err != nil {
+		return nil, err
+	}
+	return &executionPlan{template: execution, model: m, context: c}, nil
+}
+
+// execution makes the execution of input, fitting the context to it
+func (p *executionPlan) execution(input string) (*protocol.Execution, error) {
+	execution := *p.template
+	execution.Input = input
+
+	params := make(map[string]string, len(p.template.Metadata))
+	for k, v := range p.template.Metadata {
+		params[k] = v
+	}
+	execution.Metadata = params
+
+	if err := fitContext(&execution, p.context, p.model); err != nil {
+		return nil, err
+	}
+	return &execution, nil
+}
+
+// applyProtocol fills in an execution from its stored protocol definition.
+// The definition's type is used unless the request names one, and its
+// parameters act as defaults for the request parameters.
+func (s *server) applyProtocol(ctx context.Context, execution *protocol.Execution) error {
+	if execution.ProtocolID != "" {
+		p, err := s.protocolRepo.Get(ctx, execution.ProtocolID)
+		if err != nil {
+			return fmt.Errorf("protocol %s: %v", execution.ProtocolID, err)
+		}
+
+		if execution.Type == "" {
+			execution.Type = p.Type
+		}
+		params := make(map[string]string, len(p.Parameters)+len(execution.Metadata))
+		for k, v := range p.Parameters {
+			params[k] = v
+		}
+		for k, v := range execution.Metadata {
+			params[k] = v
+		}
+		execution.Metadata = params
+	}
+
+	if execution.Type != "" && !protocol.ValidType(execution.Type) {
+		return fmt.Errorf("invalid protocol type %q, must be one of %v", execution.Type, protocol.Types)
+	}
+	return nil
+}
+
+// linkedContext loads the execution's context, if any, and checks that it
+// is linked to the model, unless allowUnlinked is set
+func (s *server) linkedContext(ctx context.Context, execution *protocol.Execution, allowUnlinked bool) (*svcContext.Context, error) {
+	if execution.ContextID == "" {
+		return nil, nil
+	}
+
+	c, err := s.contextRepo.Get(ctx, execution.ContextID)
+	if err != nil {
+		return nil, err
+	}
+	if execution.ModelID != "" && !allowUnlinked && !c.HasModel(execution.ModelID) {
+		return nil, fmt.Errorf("model %s is not linked to context %s", execution.ModelID, execution.ContextID)
+	}
+	return c, nil
+}
+
+// fitContext sets the execution's context, checking that it fits in the
+// model's context window together with the input. The "truncation_policy"
+// parameter of the execution, or else of the model, decides whether an
+// oversized context fails the execution or is truncated.
+func fitContext(execution *protocol.Execution, c *svcContext.Context, m *model.Model) error {
+	if c == nil {
+		return nil
+	}
+	execution.Context = c.Content
+	if m == nil {
+		return nil
+	}
+
+	policy := execution.Metadata["truncation_policy"]
+	if policy == "" {
+		policy = m.Parameters["truncation_policy"]
+	}
+
+	var err error
+	tok := tokenizer.ForModel(m.Type, m.Parameters)
+	execution.Context, err = tokenizer.Fit(tok, c.Content, execution.Input, m.PromptBudget(), policy)
+	return err
+}
+
 // GetProtocolStatus implements the MCPServiceServer interface
 func (s *server) GetProtocolStatus(ctx context.Context, req *proto.ProtocolRequest) (*proto.ProtocolStatus, error) {
 	execution, err := s.protocolRepo.GetExecutionStatus(ctx, req.Id)
@@ -191,9 +862,68 @@ func (s *server) GetProtocolStatus(ctx context.Context, req *proto.ProtocolReque
 		return &proto.ProtocolStatus{Error: err.Error()}, nil
 	}
 
-	return &proto.ProtocolStatus{
-		Status: execution.Status,
-	}, nil
+	return statusToProto(execution), nil
+}
+
+// CancelExecution implements the MCPServiceServer interface
+func (s *server) CancelExecution(ctx context.Context, req *proto.ProtocolRequest) (*proto.ProtocolStatus, error) {
+	if s.engine.Cancel(req.Id) {
+		return &proto.ProtocolStatus{Status: protocol.StatusCancelled}, nil
+	}
+
+	// Not running in this process: cancel the stored execution if it has
+	// not finished
+	execution, err := s.protocolRepo.CancelExecution(ctx, req.Id)
+	if err != nil {
+		return &proto.ProtocolStatus{Error: err.Error()}, nil
+	}
+
+	return statusToProto(execution), nil
+}
+
+// CountTokens implements the MCPServiceServer interface
+func (s *server) CountTokens(ctx context.Context, req *proto.TokenCountRequest) (*proto.TokenCountResponse, error) {
+	var m *model.Model
+	if req.ModelId != "" {
+		var err error
+		if m, err = s.modelRepo.Resolve(ctx, req.ModelId); err != nil {
+			return &proto.TokenCountResponse{Error: err.Error()}, nil
+		}
+	}
+
+	var content string
+	if req.ContextId != "" {
+		c, err := s.contextRepo.Get(ctx, req.ContextId)
+		if err != nil {
+			return &proto.TokenCountResponse{Error: err.Error()}, nil
+		}
+		content = c.Content
+	}
+
+	return countTokens(m, content, req.Input), nil
+}
+
+// countTokens counts a context and input in the tokens of model m, or by
+// estimate without a model, and reports the prompt budget they must fit
+func countTokens(m *model.Model, content, input string) *proto.TokenCountResponse {
+	tok := tokenizer.Get(tokenizer.Estimate)
+	var window, budget int
+	if m != nil {
+		tok = tokenizer.ForModel(m.Type, m.Parameters)
+		window = m.ContextWindow()
+		budget = m.PromptBudget()
+	}
+
+	contextTokens := tok.Count(content)
+	inputTokens := tok.Count(input)
+	return &proto.TokenCountResponse{
+		ContextTokens: int32(contextTokens),
+		InputTokens:   int32(inputTokens),
+		TotalTokens:   int32(contextTokens + inputTokens),
+		ContextWindow: int32(window),
+		PromptBudget:  int32(budget),
+		Tokenizer:     tok.Name(),
+	}
 }
 
 // AddData implements the MCPServiceServer interface
@@ -280,26 +1010,18 @@ func (s *server) DeleteData(ctx context.Context, req *proto.DataRequest) (*proto
 
 // ListModels implements the MCPServiceServer interface
 func (s *server) ListModels(ctx context.Context, req *proto.ListRequest) (*proto.ModelList, error) {
-	log.Printf("ListModels called with page size: %d", req.PageSize)
-
 	if req.PageSize == 0 {
 		req.PageSize = 10 // Default page size
 	}
 
 	models, nextPageToken, err := s.modelRepo.List(ctx, int32(req.PageSize), req.Filters["pageToken"])
 	if err != nil {
-		log.Printf("Error listing models: %v", err)
 		return &proto.ModelList{Error: err.Error()}, nil
 	}
 
 	var protoModels []*proto.Model
 	for _, m := range models {
-		protoModels = append(protoModels, &proto.Model{
-			Id:         m.ID.Hex(),
-			Name:       m.Name,
-			Type:       m.Type,
-			Parameters: m.Parameters,
-		})
+		protoModels = append(protoModels, modelToProto(m))
 	}
 
 	if nextPageToken != "" && len(protoModels) > 0 {
@@ -309,7 +1031,6 @@ func (s *server) ListModels(ctx context.Context, req *proto.ListRequest) (*proto
 		protoModels[0].Parameters["nextPageToken"] = nextPageToken
 	}
 
-	log.Printf("Found %d models", len(protoModels))
 	return &proto.ModelList{
 		Models: protoModels,
 	}, nil
@@ -317,17 +1038,12 @@ func (s *server) ListModels(ctx context.Context, req *proto.ListRequest) (*proto
 
 // GetModel implements the MCPServiceServer interface
 func (s *server) GetModel(ctx context.Context, req *proto.ModelRequest) (*proto.ModelResponse, error) {
-	model, err := s.modelRepo.Get(ctx, req.Id)
+	model, err := s.modelRepo.Resolve(ctx, req.Id)
 	if err != nil {
 		return &proto.ModelResponse{Error: err.Error()}, nil
 	}
 
 	return &proto.ModelResponse{
-		Model: &proto.Model{
-			Id:         model.ID.Hex(),
-			Name:       model.Name,
-			Type:       model.Type,
-			Parameters: model.Parameters,
-		},
+		Model: modelToProto(model),
 	}, nil
 }
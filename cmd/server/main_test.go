@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/tokenizer"
+)
+
+func TestCountTokens(t *testing.T) {
+	tests := []struct {
+		name       string
+		model      *model.Model
+		wantWindow int32
+		wantBudget int32
+	}{
+		{"no model", nil, 0, 0},
+		{"unknown window", &model.Model{Type: "local", Parameters: map[string]string{"max_tokens": "100"}}, 0, 0},
+		{"window only", &model.Model{Type: "local", Parameters: map[string]string{"context_window": "8192"}}, 8192, 8192},
+		{"completion reserved", &model.Model{Type: "local", Parameters: map[string]string{"context_window": "8192", "max_tokens": "1024"}}, 8192, 7168},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := countTokens(tt.model, "12345678", "1234")
+			if got.ContextWindow != tt.wantWindow || got.PromptBudget != tt.wantBudget {
+				t.Errorf("window %d, budget %d, want %d, %d", got.ContextWindow, got.PromptBudget, tt.wantWindow, tt.wantBudget)
+			}
+			if got.ContextTokens != 2 || got.InputTokens != 1 || got.TotalTokens != 3 || got.Tokenizer != tokenizer.Estimate {
+				t.Errorf("counted %d + %d = %d with %s, want 2 + 1 = 3 with %s",
+					got.ContextTokens, got.InputTokens, got.TotalTokens, got.Tokenizer, tokenizer.Estimate)
+			}
+		})
+	}
+}
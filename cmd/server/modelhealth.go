@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/health"
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+)
+
+// defaultHealthHistory is the number of checks GetModelHealth summarizes
+// unless the request asks for another
+const defaultHealthHistory = 20
+
+// GetModelHealth implements the MCPServiceServer interface
+func (s *server) GetModelHealth(ctx context.Context, req *proto.ModelHealthRequest) (*proto.ModelHealthResponse, error) {
+	if req.History <= 0 {
+		req.History = defaultHealthHistory
+	}
+
+	var statuses []*health.Status
+	if req.ModelId != "" {
+		if _, err := s.modelRepo.Get(ctx, req.ModelId); err != nil {
+			return &proto.ModelHealthResponse{Error: err.Error()}, nil
+		}
+		status, err := s.healthRepo.Get(ctx, req.ModelId)
+		if err != nil {
+			return &proto.ModelHealthResponse{Error: err.Error()}, nil
+		}
+		statuses = append(statuses, status)
+	} else {
+		var err error
+		if statuses, err = s.healthRepo.List(ctx); err != nil {
+			return &proto.ModelHealthResponse{Error: err.Error()}, nil
+		}
+	}
+
+	resp := &proto.ModelHealthResponse{}
+	for _, status := range statuses {
+		checks, err := s.healthRepo.History(ctx, status.ModelID, int64(req.History))
+		if err != nil {
+			return &proto.ModelHealthResponse{Error: err.Error()}, nil
+		}
+
+		h := &proto.ModelHealth{
+			ModelId:             status.ModelID,
+			Status:              status.Status,
+			ConsecutiveFailures: int32(status.ConsecutiveFailures),
+		}
+		if !status.LastCheck.CheckedAt.IsZero() {
+			h.LastCheck = healthCheckToProto(&status.LastCheck)
+		}
+		var ok, latency int64
+		for _, check := range checks {
+			h.History = append(h.History, healthCheckToProto(check))
+			if check.OK {
+				ok++
+				latency += check.LatencyMs
+			}
+		}
+		if len(checks) > 0 {
+			h.Availability = float64(ok) / float64(len(checks))
+		}
+		if ok > 0 {
+			h.AverageLatencyMs = latency / ok
+		}
+		resp.Models = append(resp.Models, h)
+	}
+
+	return resp, nil
+}
+
+func healthCheckToProto(check *health.Check) *proto.HealthCheck {
+	return &proto.HealthCheck{
+		CheckedAt: timestampOrNil(check.CheckedAt),
+		Ok:        check.OK,
+		LatencyMs: check.LatencyMs,
+		Error:     check.Error,
+	}
+}
+
+// checkProviders reports an error when every probed model is unhealthy,
+// which points at the providers rather than at single models
+func checkProviders(ctx context.Context, healthRepo *health.HealthRepository) error {
+	statuses, err := healthRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	unhealthy := 0
+	for _, status := range statuses {
+		if status.Status == health.StatusUnhealthy {
+			unhealthy++
+		}
+	}
+	if unhealthy > 0 && unhealthy == len(statuses) {
+		return fmt.Errorf("all %d probed models are unhealthy", unhealthy)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateModelVersion implements the MCPServiceServer interface
+func (s *server) CreateModelVersion(ctx context.Context, req *proto.Model) (*proto.ModelResponse, error) {
+	current, err := s.modelRepo.Resolve(ctx, req.Id)
+	if err != nil {
+		return &proto.ModelResponse{Error: err.Error()}, nil
+	}
+	modelType := req.Type
+	if modelType == "" {
+		modelType = current.Type
+	}
+	params, err := structParams(req.Parameters, req.Params)
+	if err != nil {
+		return &proto.ModelResponse{Error: err.Error()}, nil
+	}
+	if err := s.checkSecret(ctx, params); err != nil {
+		return &proto.ModelResponse{Error: err.Error()}, nil
+	}
+
+	m, err := s.modelRepo.CreateVersion(ctx, current.ID.Hex(), modelType, current.Description, params)
+	if err != nil {
+		return &proto.ModelResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ModelResponse{
+		Model: modelToProto(m),
+	}, nil
+}
+
+// ListModelVersions implements the MCPServiceServer interface
+func (s *server) ListModelVersions(ctx context.Context, req *proto.ModelRequest) (*proto.ModelList, error) {
+	m, err := s.modelRepo.Resolve(ctx, req.Id)
+	if err != nil {
+		return &proto.ModelList{Error: err.Error()}, nil
+	}
+
+	versions, err := s.modelRepo.ListVersions(ctx, m.ID.Hex())
+	if err != nil {
+		return &proto.ModelList{Error: err.Error()}, nil
+	}
+
+	var protoModels []*proto.Model
+	for _, v := range versions {
+		protoModels = append(protoModels, &proto.Model{
+			Id:         v.ModelID,
+			Name:       m.Name,
+			Type:       v.Type,
+			Parameters: v.Parameters,
+			Params:     paramsStruct(v.Type, v.Parameters),
+			Version:    int32(v.Number),
+		})
+	}
+
+	return &proto.ModelList{
+		Models: protoModels,
+	}, nil
+}
+
+// SetModelAlias implements the MCPServiceServer interface
+func (s *server) SetModelAlias(ctx context.Context, req *proto.ModelAlias) (*proto.ModelAliasResponse, error) {
+	m, err := s.modelRepo.Resolve(ctx, req.ModelId)
+	if err != nil {
+		return &proto.ModelAliasResponse{Error: err.Error()}, nil
+	}
+
+	alias, err := s.modelRepo.SetAlias(ctx, m.ID.Hex(), req.Alias, int(req.Version))
+	if err != nil {
+		return &proto.ModelAliasResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ModelAliasResponse{
+		Alias: aliasToProto(alias),
+	}, nil
+}
+
+// ListModelAliases implements the MCPServiceServer interface
+func (s *server) ListModelAliases(ctx context.Context, req *proto.ModelRequest) (*proto.ModelAliasList, error) {
+	m, err := s.modelRepo.Resolve(ctx, req.Id)
+	if err != nil {
+		return &proto.ModelAliasList{Error: err.Error()}, nil
+	}
+
+	aliases, err := s.modelRepo.ListAliases(ctx, m.ID.Hex())
+	if err != nil {
+		return &proto.ModelAliasList{Error: err.Error()}, nil
+	}
+
+	var protoAliases []*proto.ModelAlias
+	for _, a := range aliases {
+		protoAliases = append(protoAliases, aliasToProto(a))
+	}
+
+	return &proto.ModelAliasList{
+		Aliases: protoAliases,
+	}, nil
+}
+
+// DeleteModelAlias implements the MCPServiceServer interface
+func (s *server) DeleteModelAlias(ctx context.Context, req *proto.ModelAlias) (*proto.DeleteResponse, error) {
+	m, err := s.modelRepo.Resolve(ctx, req.ModelId)
+	if err != nil {
+		return &proto.DeleteResponse{Error: err.Error()}, nil
+	}
+
+	if err := s.modelRepo.DeleteAlias(ctx, m.ID.Hex(), req.Alias); err != nil {
+		return &proto.DeleteResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.DeleteResponse{
+		Success: true,
+	}, nil
+}
+
+// resolveModel points an execution at the model version its model
+// reference resolves to, keeping the reference it was started with, and
+// returns that version. Executions without a model return nil.
+func (s *server) resolveModel(ctx context.Context, execution *protocol.Execution) (*model.Model, error) {
+	if execution.ModelID == "" {
+		return nil, nil
+	}
+
+	m, err := s.modelRepo.Resolve(ctx, execution.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	if execution.ModelID != m.ID.Hex() {
+		execution.ModelRef = execution.ModelID
+	}
+	execution.ModelID = m.ID.Hex()
+	execution.ModelVersion = m.Version
+	return m, nil
+}
+
+// modelFilter resolves the model reference a listing or report is
+// filtered by to the model ID executions store, so that names and aliases
+// filter the same way. IDs of deleted models still match.
+func (s *server) modelFilter(ctx context.Context, ref string) (string, error) {
+	m, err := s.modelRepo.Resolve(ctx, ref)
+	switch {
+	case err == nil:
+		return m.ID.Hex(), nil
+	case primitive.IsValidObjectID(ref):
+		return ref, nil
+	default:
+		return "", err
+	}
+}
+
+// modelToProto converts a stored model to its API representation
+func modelToProto(m *model.Model) *proto.Model {
+	return &proto.Model{
+		Id:         m.ID.Hex(),
+		Name:       m.Name,
+		Type:       m.Type,
+		Parameters: m.Parameters,
+		Params:     paramsStruct(m.Type, m.Parameters),
+		Version:    int32(m.Version),
+	}
+}
+
+func aliasToProto(a *model.Alias) *proto.ModelAlias {
+	return &proto.ModelAlias{
+		ModelId:   a.ModelID,
+		Alias:     a.Alias,
+		Version:   int32(a.Version),
+		Ref:       a.Ref,
+		UpdatedAt: timestampOrNil(a.UpdatedAt),
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testMongoURIEnv names the environment variable holding the URI of a
+// MongoDB server for the handler tests. They are skipped without it.
+const testMongoURIEnv = "MCP_TEST_MONGODB_URI"
+
+// testDatabase returns a scratch database, dropped when the test ends
+func testDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv(testMongoURIEnv)
+	if uri == "" {
+		t.Skipf("%s not set", testMongoURIEnv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	db := client.Database("mcp_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		db.Drop(ctx)
+		client.Disconnect(ctx)
+	})
+	return db
+}
+
+func TestModelFilter(t *testing.T) {
+	s := &server{modelRepo: model.NewModelRepository(testDatabase(t))}
+	ctx := context.Background()
+
+	m := &model.Model{Name: "reviewer", Type: "gpt-4"}
+	if err := s.modelRepo.Create(ctx, m); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := s.modelRepo.SetAlias(ctx, m.ID.Hex(), "prod", 1); err != nil {
+		t.Fatalf("SetAlias: %v", err)
+	}
+	deleted := primitive.NewObjectID().Hex()
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{m.ID.Hex(), m.ID.Hex(), false},
+		{"reviewer", m.ID.Hex(), false},
+		{"reviewer@prod", m.ID.Hex(), false},
+		{"reviewer@v1", m.ID.Hex(), false},
+		{deleted, deleted, false},
+		{"unknown", "", true},
+	}
+	for _, tt := range tests {
+		got, err := s.modelFilter(ctx, tt.ref)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("modelFilter(%q) = %q, %v, want %q", tt.ref, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// GetModelSchema implements the MCPServiceServer interface
+func (s *server) GetModelSchema(ctx context.Context, req *proto.ModelSchemaRequest) (*proto.ModelSchema, error) {
+	resp := &proto.ModelSchema{
+		Type:       req.Type,
+		Parameters: make(map[string]*proto.ParameterSchema),
+	}
+	for name, p := range model.SchemaFor(req.Type) {
+		resp.Parameters[name] = &proto.ParameterSchema{
+			Type:        p.Type,
+			Min:         p.Min,
+			Max:         p.Max,
+			Enum:        p.Enum,
+			Default:     p.Default,
+			Required:    p.Required,
+			Description: p.Description,
+			ModelOnly:   p.ModelOnly,
+		}
+	}
+	return resp, nil
+}
+
+// structParams merges typed parameters over string ones. Parameters are
+// stored as strings: numbers and booleans are formatted, and lists and
+// objects encoded as JSON.
+func structParams(params map[string]string, typed *structpb.Struct) (map[string]string, error) {
+	if len(typed.GetFields()) == 0 {
+		return params, nil
+	}
+
+	merged := make(map[string]string, len(params)+len(typed.Fields))
+	for k, v := range params {
+		merged[k] = v
+	}
+	for k, v := range typed.Fields {
+		switch kind := v.Kind.(type) {
+		case *structpb.Value_NullValue:
+			delete(merged, k)
+		case *structpb.Value_StringValue:
+			merged[k] = kind.StringValue
+		case *structpb.Value_NumberValue:
+			merged[k] = strconv.FormatFloat(kind.NumberValue, 'f', -1, 64)
+		case *structpb.Value_BoolValue:
+			merged[k] = strconv.FormatBool(kind.BoolValue)
+		default:
+			b, err := json.Marshal(v.AsInterface())
+			if err != nil {
+				return nil, fmt.Errorf("parameter %s: %v", k, err)
+			}
+			merged[k] = string(b)
+		}
+	}
+	return merged, nil
+}
+
+// paramsStruct returns a model's parameters typed by its type's schema
+func paramsStruct(modelType string, params map[string]string) *structpb.Struct {
+	s, err := structpb.NewStruct(model.SchemaFor(modelType).Typed(params))
+	if err != nil {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/schedule"
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+)
+
+// CreateSchedule implements the MCPServiceServer interface
+func (s *server) CreateSchedule(ctx context.Context, req *proto.Schedule) (*proto.ScheduleResponse, error) {
+	sch := &schedule.Schedule{
+		Name:            req.Name,
+		Cron:            req.Cron,
+		IntervalSeconds: int(req.IntervalSeconds),
+		Paused:          req.Paused,
+	}
+	if req.Request != nil {
+		params, err := structParams(req.Request.Parameters, req.Request.Params)
+		if err != nil {
+			return &proto.ScheduleResponse{Error: err.Error()}, nil
+		}
+		sch.Request = requestFromProto(req.Request)
+		sch.Request.Parameters = params
+		if err := s.checkOverrides(ctx, sch.Request.ModelID, params); err != nil {
+			return &proto.ScheduleResponse{Error: err.Error()}, nil
+		}
+	}
+
+	if err := s.scheduleRepo.Create(ctx, sch); err != nil {
+		return &proto.ScheduleResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ScheduleResponse{
+		Schedule: scheduleToProto(sch),
+	}, nil
+}
+
+// ListSchedules implements the MCPServiceServer interface
+func (s *server) ListSchedules(ctx context.Context, req *proto.ListRequest) (*proto.ScheduleList, error) {
+	if req.PageSize == 0 {
+		req.PageSize = 10 // Default page size
+	}
+
+	schedules, nextPageToken, err := s.scheduleRepo.List(ctx, req.PageSize, req.Filters["pageToken"])
+	if err != nil {
+		return &proto.ScheduleList{Error: err.Error()}, nil
+	}
+
+	var protoSchedules []*proto.Schedule
+	for _, sch := range schedules {
+		protoSchedules = append(protoSchedules, scheduleToProto(sch))
+	}
+
+	return &proto.ScheduleList{
+		Schedules:     protoSchedules,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// PauseSchedule implements the MCPServiceServer interface
+func (s *server) PauseSchedule(ctx context.Context, req *proto.PauseScheduleRequest) (*proto.ScheduleResponse, error) {
+	sch, err := s.scheduleRepo.SetPaused(ctx, req.Id, req.Paused)
+	if err != nil {
+		return &proto.ScheduleResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.ScheduleResponse{
+		Schedule: scheduleToProto(sch),
+	}, nil
+}
+
+// DeleteSchedule implements the MCPServiceServer interface
+func (s *server) DeleteSchedule(ctx context.Context, req *proto.ScheduleRequest) (*proto.DeleteResponse, error) {
+	if err := s.scheduleRepo.Delete(ctx, req.Id); err != nil {
+		return &proto.DeleteResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.DeleteResponse{
+		Success: true,
+	}, nil
+}
+
+// checkOverrides validates the parameters a schedule overrides its model's
+// with, as ExecuteProtocol does on every run, so that invalid or model-only
+// overrides are rejected up front
+func (s *server) checkOverrides(ctx context.Context, modelID string, params map[string]string) error {
+	modelType := ""
+	if modelID != "" {
+		m, err := s.modelRepo.Resolve(ctx, modelID)
+		if err != nil {
+			return err
+		}
+		modelType = m.Type
+	}
+	return model.SchemaFor(modelType).ValidateOverrides(params)
+}
+
+// runSchedule starts a scheduled execution the way ExecuteProtocol does
+func (s *server) runSchedule(ctx context.Context, req schedule.Request) (string, error) {
+	resp, err := s.ExecuteProtocol(ctx, requestToProto(req))
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", errors.New(resp.Error)
+	}
+	return resp.Id, nil
+}
+
+func requestFromProto(p *proto.Protocol) schedule.Request {
+	return schedule.Request{
+		ProtocolID:    p.ProtocolId,
+		Type:          p.Type,
+		ModelID:       p.ModelId,
+		ContextID:     p.ContextId,
+		Input:         p.Input,
+		Parameters:    p.Parameters,
+		AllowUnlinked: p.AllowUnlinked,
+		Priority:      p.Priority,
+		Tenant:        p.Tenant,
+	}
+}
+
+func requestToProto(r schedule.Request) *proto.Protocol {
+	return &proto.Protocol{
+		ProtocolId:    r.ProtocolID,
+		Type:          r.Type,
+		ModelId:       r.ModelID,
+		ContextId:     r.ContextID,
+		Input:         r.Input,
+		Parameters:    r.Parameters,
+		AllowUnlinked: r.AllowUnlinked,
+		Priority:      r.Priority,
+		Tenant:        r.Tenant,
+	}
+}
+
+// scheduleToProto converts a stored schedule to its API representation
+func scheduleToProto(sch *schedule.Schedule) *proto.Schedule {
+	return &proto.Schedule{
+		Id:              sch.ID.Hex(),
+		Name:            sch.Name,
+		Cron:            sch.Cron,
+		IntervalSeconds: int64(sch.IntervalSeconds),
+		Paused:          sch.Paused,
+		Request:         requestToProto(sch.Request),
+		LastRunAt:       timestampOrNil(sch.LastRunAt),
+		NextRunAt:       timestampOrNil(sch.NextRunAt),
+		LastExecutionId: sch.LastExecutionID,
+		LastError:       sch.LastError,
+	}
+}
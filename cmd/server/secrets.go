@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/provider"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/secret"
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PutSecret implements the MCPServiceServer interface
+func (s *server) PutSecret(ctx context.Context, req *proto.Secret) (*proto.SecretResponse, error) {
+	sec, err := s.secretRepo.Put(ctx, req.Name, req.Value, req.Description, req.Hosts)
+	if err != nil {
+		return &proto.SecretResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.SecretResponse{
+		Secret: secretToProto(sec),
+	}, nil
+}
+
+// ListSecrets implements the MCPServiceServer interface
+func (s *server) ListSecrets(ctx context.Context, req *proto.ListRequest) (*proto.SecretList, error) {
+	secrets, err := s.secretRepo.List(ctx)
+	if err != nil {
+		return &proto.SecretList{Error: err.Error()}, nil
+	}
+
+	var protoSecrets []*proto.Secret
+	for _, sec := range secrets {
+		protoSecrets = append(protoSecrets, secretToProto(sec))
+	}
+
+	return &proto.SecretList{
+		Secrets: protoSecrets,
+	}, nil
+}
+
+// DeleteSecret implements the MCPServiceServer interface
+func (s *server) DeleteSecret(ctx context.Context, req *proto.SecretRequest) (*proto.DeleteResponse, error) {
+	if err := s.secretRepo.Delete(ctx, req.Name); err != nil {
+		return &proto.DeleteResponse{Error: err.Error()}, nil
+	}
+
+	return &proto.DeleteResponse{
+		Success: true,
+	}, nil
+}
+
+// checkSecret reports an error when params reference a secret that does
+// not exist or may not be sent to their base_url, so that models fail when
+// created rather than when called
+func (s *server) checkSecret(ctx context.Context, params map[string]string) error {
+	name := params["api_key_secret"]
+	if name == "" {
+		return nil
+	}
+	sec, err := s.secretRepo.Get(ctx, name)
+	if err != nil {
+		return fmt.Errorf("api_key_secret: %v", err)
+	}
+	host, err := provider.BaseURLHost(params)
+	if err != nil {
+		return err
+	}
+	if !sec.AllowsHost(host) {
+		return fmt.Errorf("api_key_secret: secret %s may not be sent to %s; add the host to the secret", name, host)
+	}
+	return nil
+}
+
+// secretToProto converts a secret to its proto form, which never carries
+// the value
+func secretToProto(sec *secret.Secret) *proto.Secret {
+	return &proto.Secret{
+		Name:        sec.Name,
+		Description: sec.Description,
+		Hosts:       sec.Hosts,
+		KeyId:       sec.KeyID,
+		CreatedAt:   timestamppb.New(sec.CreatedAt),
+		UpdatedAt:   timestamppb.New(sec.UpdatedAt),
+	}
+}
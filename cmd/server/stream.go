@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/engine"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// eventSender is implemented by the server streams of StreamExecution and
+// WatchExecution
+type eventSender interface {
+	Send(*proto.ExecutionEvent) error
+}
+
+// StreamExecution implements the MCPServiceServer interface
+func (s *server) StreamExecution(req *proto.Protocol, stream proto.MCPService_StreamExecutionServer) error {
+	resp, err := s.ExecuteProtocol(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return stream.Send(&proto.ExecutionEvent{
+			Type:   engine.EventStatus,
+			Status: protocol.StatusFailed,
+			Error:  resp.Error,
+		})
+	}
+
+	return s.watchExecution(stream.Context(), resp.Id, stream)
+}
+
+// WatchExecution implements the MCPServiceServer interface
+func (s *server) WatchExecution(req *proto.ProtocolRequest, stream proto.MCPService_WatchExecutionServer) error {
+	return s.watchExecution(stream.Context(), req.Id, stream)
+}
+
+// watchExecution replays the buffered events of an execution and follows
+// it until it finishes. Executions that are not running in this process
+// are followed through the database.
+func (s *server) watchExecution(ctx context.Context, id string, stream eventSender) error {
+	var last int64
+	for {
+		replay, events, cancel, ok := s.engine.Watch(id)
+		if !ok {
+			return s.followStoredExecution(ctx, id, last, stream)
+		}
+
+		before := last
+		finished, err := forwardEvents(ctx, replay, events, &last, stream)
+		cancel()
+		if err != nil || finished {
+			return err
+		}
+		if last == before {
+			// The stream ended without a final status: the execution was
+			// claimed by another server
+			return s.followStoredExecution(ctx, id, last, stream)
+		}
+		// The event channel was closed because this watcher fell behind;
+		// subscribe again and continue after the last event sent.
+	}
+}
+
+// forwardEvents sends the events after sequence last, reporting whether the
+// final status of the execution was among them
+func forwardEvents(ctx context.Context, replay []engine.Event, events <-chan engine.Event, last *int64, stream eventSender) (bool, error) {
+	send := func(ev engine.Event) (bool, error) {
+		if ev.Sequence <= *last {
+			return false, nil
+		}
+		*last = ev.Sequence
+		if err := stream.Send(eventToProto(ev)); err != nil {
+			return false, err
+		}
+		return ev.Type == engine.EventStatus && protocol.Finished(ev.Status), nil
+	}
+
+	for _, ev := range replay {
+		if finished, err := send(ev); finished || err != nil {
+			return finished, err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case ev, open := <-events:
+			if !open {
+				return false, nil
+			}
+			if finished, err := send(ev); finished || err != nil {
+				return finished, err
+			}
+		}
+	}
+}
+
+// storedPollInterval is how often executions running elsewhere are
+// checked for progress
+const storedPollInterval = time.Second
+
+// followStoredExecution polls the stored state of an execution, sending its
+// step transitions and status changes until it finishes
+func (s *server) followStoredExecution(ctx context.Context, id string, last int64, stream eventSender) error {
+	var status string
+	var steps int
+	for {
+		execution, err := s.protocolRepo.GetExecutionStatus(ctx, id)
+		if err != nil {
+			return stream.Send(&proto.ExecutionEvent{
+				ExecutionId: id,
+				Type:        engine.EventStatus,
+				Error:       err.Error(),
+			})
+		}
+
+		if len(execution.Steps) < steps {
+			// The execution was reclaimed and started over
+			steps = 0
+		}
+		for _, step := range execution.Steps[steps:] {
+			last++
+			if err := stream.Send(&proto.ExecutionEvent{
+				ExecutionId: id,
+				Sequence:    last,
+				Type:        engine.EventStep,
+				StepId:      step.ID,
+				Status:      step.Status,
+				Output:      step.Output,
+				Error:       step.Error,
+				Time:        timestamppb.New(step.FinishedAt),
+			}); err != nil {
+				return err
+			}
+		}
+		steps = len(execution.Steps)
+
+		if execution.Status != status {
+			status = execution.Status
+			last++
+			if err := stream.Send(&proto.ExecutionEvent{
+				ExecutionId: id,
+				Sequence:    last,
+				Type:        engine.EventStatus,
+				Status:      execution.Status,
+				Output:      execution.Result,
+				Error:       execution.Error,
+				Time:        timestamppb.New(execution.UpdatedAt),
+			}); err != nil {
+				return err
+			}
+		}
+		if protocol.Finished(execution.Status) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(storedPollInterval):
+		}
+	}
+}
+
+func eventToProto(ev engine.Event) *proto.ExecutionEvent {
+	return &proto.ExecutionEvent{
+		ExecutionId: ev.ExecutionID,
+		Sequence:    ev.Sequence,
+		Type:        ev.Type,
+		StepId:      ev.StepID,
+		Delta:       ev.Delta,
+		Status:      ev.Status,
+		Output:      ev.Output,
+		Error:       ev.Error,
+		Time:        timestamppb.New(ev.Time),
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"github.com/DavutcanJ/mongo-mcp-server/internal/changes"
+	svcContext "github.com/DavutcanJ/mongo-mcp-server/internal/service/context"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/data"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Subscribe implements the MCPServiceServer interface
+func (s *server) Subscribe(req *proto.SubscribeRequest, stream proto.MCPService_SubscribeServer) error {
+	filter := changes.Filter{
+		Entities:   req.Entities,
+		Operations: req.Operations,
+		IDs:        req.Ids,
+	}
+
+	err := s.changes.Subscribe(stream.Context(), filter, req.ResumeToken, func(ev changes.Event) error {
+		resp := &proto.ChangeEvent{
+			Entity:      ev.Entity,
+			Operation:   ev.Operation,
+			Id:          ev.ID,
+			Time:        timestamppb.New(ev.Time),
+			ResumeToken: ev.Token,
+		}
+		if req.IncludeDocuments && ev.Document != nil {
+			if err := setDocument(resp, ev); err != nil {
+				resp.Error = err.Error()
+			}
+		}
+		return stream.Send(resp)
+	})
+	if err != nil && stream.Context().Err() == nil {
+		return stream.Send(&proto.ChangeEvent{Error: err.Error()})
+	}
+	return nil
+}
+
+// setDocument decodes the changed entity into the event
+func setDocument(resp *proto.ChangeEvent, ev changes.Event) error {
+	switch ev.Entity {
+	case changes.EntityModel:
+		var m model.Model
+		if err := bson.Unmarshal(ev.Document, &m); err != nil {
+			return err
+		}
+		resp.Document = &proto.ChangeEvent_Model{Model: modelToProto(&m)}
+	case changes.EntityContext:
+		var c svcContext.Context
+		if err := bson.Unmarshal(ev.Document, &c); err != nil {
+			return err
+		}
+		resp.Document = &proto.ChangeEvent_Context{Context: contextToProto(&c)}
+	case changes.EntityData:
+		var d data.Data
+		if err := bson.Unmarshal(ev.Document, &d); err != nil {
+			return err
+		}
+		resp.Document = &proto.ChangeEvent_Data{Data: dataToProto(&d)}
+	case changes.EntityExecution:
+		var e protocol.Execution
+		if err := bson.Unmarshal(ev.Document, &e); err != nil {
+			return err
+		}
+		resp.Document = &proto.ChangeEvent_Execution{Execution: executionToProto(&e)}
+	}
+	return nil
+}
+
+func dataToProto(d *data.Data) *proto.Data {
+	return &proto.Data{
+		Id:       d.ID.Hex(),
+		Type:     d.Type,
+		Content:  []byte(d.Content),
+		Metadata: d.Metadata,
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+	"google.golang.org/grpc/metadata"
+)
+
+// apiKeyHeader is the request metadata carrying the caller's API key
+const apiKeyHeader = "x-api-key"
+
+// GetUsageReport implements the MCPServiceServer interface
+func (s *server) GetUsageReport(ctx context.Context, req *proto.UsageReportRequest) (*proto.UsageReport, error) {
+	filter := protocol.UsageFilter{
+		Tenant:   req.Tenant,
+		APIKeyID: req.ApiKey,
+	}
+	if req.ModelId != "" {
+		modelID, err := s.modelFilter(ctx, req.ModelId)
+		if err != nil {
+			return &proto.UsageReport{Error: err.Error()}, nil
+		}
+		filter.ModelID = modelID
+	}
+	if req.Start != nil {
+		filter.Start = req.Start.AsTime()
+	}
+	if req.End != nil {
+		filter.End = req.End.AsTime()
+	}
+
+	rows, err := s.protocolRepo.UsageReport(ctx, req.GroupBy, filter)
+	if err != nil {
+		return &proto.UsageReport{Error: err.Error()}, nil
+	}
+
+	report := &proto.UsageReport{Total: &proto.UsageRow{}}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, &proto.UsageRow{
+			ModelId:          row.Group.ModelID,
+			Tenant:           row.Group.Tenant,
+			ApiKey:           row.Group.APIKeyID,
+			Day:              row.Group.Day,
+			Executions:       row.Executions,
+			Calls:            row.Calls,
+			PromptTokens:     row.PromptTokens,
+			CompletionTokens: row.CompletionTokens,
+			Cost:             row.Cost,
+		})
+		// An execution can fall in several groups, so its total is only
+		// exact when there is a single group
+		report.Total.Executions += row.Executions
+		report.Total.Calls += row.Calls
+		report.Total.PromptTokens += row.PromptTokens
+		report.Total.CompletionTokens += row.CompletionTokens
+		report.Total.Cost += row.Cost
+	}
+
+	return report, nil
+}
+
+// apiKeyID identifies the API key a request was made with, without
+// storing the key itself. Requests without a key return "".
+func apiKeyID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	keys := md.Get(apiKeyHeader)
+	if len(keys) == 0 || keys[0] == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(keys[0]))
+	return hex.EncodeToString(sum[:6])
+}
@@ -18,17 +18,59 @@ func printUsage() {
 	fmt.Println("    create <name> <type> [parameters]")
 	fmt.Println("    get <id>")
 	fmt.Println("    list")
+	fmt.Println("    version <id> <type> [parameters]")
+	fmt.Println("    versions <id>")
+	fmt.Println("    alias <id> <alias> [version]")
+	fmt.Println("    aliases <id>")
+	fmt.Println("    unalias <id> <alias>")
+	fmt.Println("    health [--history <n>] [model_id]")
+	fmt.Println("    schema <type>")
 	fmt.Println("\n  context:")
 	fmt.Println("    create <name> <content> [metadata]")
 	fmt.Println("    get <id>")
 	fmt.Println("    list")
-	fmt.Println("\n  execute <model_id> <context_id> <input> [parameters]")
-	fmt.Println("  status <execution_id>")
+	fmt.Println("    link <context_id> <model_id>")
+	fmt.Println("    unlink <context_id> <model_id>")
+	fmt.Println("    for-model <model_id>")
+	fmt.Println("\n  protocol:")
+	fmt.Println("    create <name> <type> [steps] [parameters]")
+	fmt.Println("    get <id>")
+	fmt.Println("    list")
+	fmt.Println("    update <id> <name> <type> [steps] [parameters]")
+	fmt.Println("    delete <id>")
+	fmt.Println("\n  execute [--allow-unlinked] [--protocol <protocol_id>] [--follow] [--priority interactive|batch] [--tenant <tenant>] <model_id> <context_id> <input> [parameters]")
+	fmt.Println("  status [--wait] [--interval <duration>] <execution_id>")
+	fmt.Println("  watch <execution_id>")
+	fmt.Println("  cancel <execution_id>")
+	fmt.Println("\n  executions:")
+	fmt.Println("    list [--model <id>] [--context <id>] [--protocol <id>] [--status <status>] [--tenant <tenant>] [--since <time>] [--until <time>] [--page <token>]")
+	fmt.Println("    show <id>")
+	fmt.Println("\n  batch:")
+	fmt.Println("    run (--inputs <file> | --data-type <type> [--data-filter <json>]) [execute options] <model_id> <context_id> [parameters]")
+	fmt.Println("    status [--wait] <id>")
+	fmt.Println("    export [--output <file>] <id>")
+	fmt.Println("  queue [--tenant <tenant>]")
+	fmt.Println("  cache purge [model_id]")
+	fmt.Println("  usage [--by model,tenant,api_key,day] [--model <id>] [--tenant <tenant>] [--api-key <fingerprint>] [--since <time>] [--until <time>]")
+	fmt.Println("\n  schedule:")
+	fmt.Println("    create <name> (--cron <expression> | --every <duration>) [execute options] <model_id> <context_id> <input> [parameters]")
+	fmt.Println("    list")
+	fmt.Println("    pause <id>")
+	fmt.Println("    resume <id>")
+	fmt.Println("    delete <id>")
+	fmt.Println("  tokens <model_id> <context_id> <input>")
+	fmt.Println("  health [component]")
+	fmt.Println("  log-level [debug|info|warn|error]")
+	fmt.Println("  subscribe [--entity model,context,data,execution] [--operation created,updated,deleted] [--id <id>,...] [--resume <token>] [--documents]")
 	fmt.Println("\n  data:")
 	fmt.Println("    add <type> <content> [metadata]")
 	fmt.Println("    get <id>")
 	fmt.Println("    list")
 	fmt.Println("    delete <id>")
+	fmt.Println("\n  secret:")
+	fmt.Println("    set [--description <text>] [--hosts <host,...>] <name>   (value read from stdin)")
+	fmt.Println("    list")
+	fmt.Println("    delete <name>")
 }
 
 func main() {
@@ -43,16 +85,22 @@ func main() {
 		log.Fatalf("Failed to create integration: %v", err)
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
 	// Handle command
 	command := os.Args[1]
 	args := os.Args[2:]
 
+	// Create context with timeout, except for commands following an
+	// execution until it finishes
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if command == "watch" || command == "subscribe" || hasArg(args, "--follow") || hasArg(args, "--wait") || (command == "batch" && hasArg(args, "export")) {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	defer cancel()
+
 	result, err := integration.HandleCommand(ctx, command, args)
 	if err != nil {
+		// Commands such as health report what they found before failing
+		fmt.Print(result)
 		log.Fatalf("Command failed: %v", err)
 	}
 
@@ -61,3 +109,12 @@ func main() {
 		fmt.Println(result)
 	}
 }
+
+func hasArg(args []string, arg string) bool {
+	for _, a := range args {
+		if a == arg {
+			return true
+		}
+	}
+	return false
+}
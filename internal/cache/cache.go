@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Entry is a cached execution result
+type Entry struct {
+	Key       string    `bson:"_id" json:"key"`
+	ModelID   string    `bson:"model_id" json:"model_id"`
+	Result    string    `bson:"result" json:"result"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// Key returns the canonical hash of v. Maps are encoded with sorted keys,
+// so equal values always hash alike.
+func Key(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Cache stores execution results in MongoDB, with the most recently used
+// entries also kept in memory
+type Cache struct {
+	collection *mongo.Collection
+	size       int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+// NewCache creates a new Cache holding up to size entries in memory
+func NewCache(db *mongo.Database, size int) *Cache {
+	if size <= 0 {
+		size = 1000
+	}
+	return &Cache{
+		collection: db.Collection("cache"),
+		size:       size,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// EnsureIndexes lets MongoDB remove expired entries
+func (c *Cache) EnsureIndexes(ctx context.Context) error {
+	_, err := c.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"expires_at": 1}, Options: options.Index().SetExpireAfterSeconds(0)},
+		{Keys: bson.M{"model_id": 1}},
+	})
+	return err
+}
+
+// Get returns the unexpired entry stored under key
+func (c *Cache) Get(ctx context.Context, key string) (*Entry, bool, error) {
+	if entry, ok := c.lookup(key); ok {
+		return entry, true, nil
+	}
+
+	var entry Entry
+	filter := bson.M{"_id": key, "expires_at": bson.M{"$gt": time.Now()}}
+	err := c.collection.FindOne(ctx, filter).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	c.remember(&entry)
+	return &entry, true, nil
+}
+
+// Put stores an entry until ttl from now
+func (c *Cache) Put(ctx context.Context, entry *Entry, ttl time.Duration) error {
+	entry.CreatedAt = time.Now()
+	entry.ExpiresAt = entry.CreatedAt.Add(ttl)
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := c.collection.ReplaceOne(ctx, bson.M{"_id": entry.Key}, entry, opts); err != nil {
+		return err
+	}
+
+	c.remember(entry)
+	return nil
+}
+
+// Purge removes the entries of a model, or all entries when modelID is
+// empty, and returns how many were stored. Only this process's memory is
+// cleared: other servers sharing the database keep serving the entries
+// they hold in memory until these expire or are evicted.
+func (c *Cache) Purge(ctx context.Context, modelID string) (int64, error) {
+	filter := bson.M{}
+	if modelID != "" {
+		filter["model_id"] = modelID
+	}
+
+	c.mu.Lock()
+	for key, el := range c.entries {
+		if modelID == "" || el.Value.(*Entry).ModelID == modelID {
+			c.order.Remove(el)
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+
+	result, err := c.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// lookup returns an unexpired entry held in memory
+func (c *Cache) lookup(key string) (*Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*Entry)
+	if !time.Now().Before(entry.ExpiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry, true
+}
+
+// remember keeps an entry in memory, evicting the least recently used
+func (c *Cache) remember(entry *Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[entry.Key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[entry.Key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*Entry).Key)
+	}
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"container/list"
+	"testing"
+	"time"
+)
+
+// memory returns a Cache holding up to size entries in memory, without a
+// database
+func memory(size int) *Cache {
+	return &Cache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func entry(key string, ttl time.Duration) *Entry {
+	return &Entry{Key: key, Result: "result " + key, ExpiresAt: time.Now().Add(ttl)}
+}
+
+func TestLRU(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		ops     []string // "+key" remembers key, "?key" looks it up
+		present []string
+		absent  []string
+	}{
+		{"holds up to size", 2, []string{"+a", "+b"}, []string{"a", "b"}, nil},
+		{"evicts the oldest", 2, []string{"+a", "+b", "+c"}, []string{"b", "c"}, []string{"a"}},
+		{"lookups refresh", 2, []string{"+a", "+b", "?a", "+c"}, []string{"a", "c"}, []string{"b"}},
+		{"remembering again refreshes", 2, []string{"+a", "+b", "+a", "+c"}, []string{"a", "c"}, []string{"b"}},
+		{"missing lookups change nothing", 2, []string{"+a", "?x", "+b"}, []string{"a", "b"}, []string{"x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := memory(tt.size)
+			for _, op := range tt.ops {
+				key := op[1:]
+				if op[0] == '+' {
+					c.remember(entry(key, time.Hour))
+				} else {
+					c.lookup(key)
+				}
+			}
+			for _, key := range tt.present {
+				got, ok := c.lookup(key)
+				if !ok || got.Result != "result "+key {
+					t.Errorf("lookup(%q) = %v, %v, want its entry", key, got, ok)
+				}
+			}
+			for _, key := range tt.absent {
+				if _, ok := c.lookup(key); ok {
+					t.Errorf("lookup(%q) found an entry, want none", key)
+				}
+			}
+			if c.order.Len() != len(c.entries) || len(c.entries) > tt.size {
+				t.Errorf("holds %d entries in a list of %d, want at most %d", len(c.entries), c.order.Len(), tt.size)
+			}
+		})
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	c := memory(2)
+	c.remember(entry("old", -time.Second))
+	c.remember(entry("new", time.Hour))
+
+	if _, ok := c.lookup("old"); ok {
+		t.Error("lookup returned an expired entry")
+	}
+	if _, ok := c.entries["old"]; ok {
+		t.Error("expired entry kept in memory after lookup")
+	}
+	if _, ok := c.lookup("new"); !ok {
+		t.Error("lookup missed an unexpired entry")
+	}
+}
+
+func TestLRUReplace(t *testing.T) {
+	c := memory(2)
+	c.remember(entry("a", time.Hour))
+	replaced := entry("a", time.Hour)
+	replaced.Result = "replaced"
+	c.remember(replaced)
+
+	got, ok := c.lookup("a")
+	if !ok || got.Result != "replaced" {
+		t.Errorf("lookup = %v, %v, want the replaced entry", got, ok)
+	}
+	if c.order.Len() != 1 {
+		t.Errorf("holds %d entries, want 1", c.order.Len())
+	}
+}
+
+func TestKey(t *testing.T) {
+	a, err := Key(map[string]string{"x": "1", "y": "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Key(map[string]string{"y": "2", "x": "1"})
+	c, _ := Key(map[string]string{"x": "1", "y": "3"})
+	if a != b {
+		t.Error("equal maps hash differently")
+	}
+	if a == c {
+		t.Error("different maps hash alike")
+	}
+}
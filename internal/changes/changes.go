@@ -0,0 +1,173 @@
+package changes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Entities whose changes are published
+const (
+	EntityModel     = "model"
+	EntityContext   = "context"
+	EntityData      = "data"
+	EntityExecution = "execution"
+)
+
+// Operations changing an entity
+const (
+	OperationCreated = "created"
+	OperationUpdated = "updated"
+	OperationDeleted = "deleted"
+)
+
+// collections holds the collection storing each entity
+var collections = map[string]string{
+	EntityModel:     "models",
+	EntityContext:   "contexts",
+	EntityData:      "data",
+	EntityExecution: "executions",
+}
+
+var operations = []string{OperationCreated, OperationUpdated, OperationDeleted}
+
+// ErrInvalidToken is returned for resume tokens this feed cannot resume from
+var ErrInvalidToken = errors.New("invalid resume token")
+
+// Event is a change of an entity
+type Event struct {
+	Entity    string
+	Operation string
+	ID        string
+	Document  bson.Raw // the entity after the change, nil when deleted
+	Time      time.Time
+	Token     string // resumes a subscription after this event
+}
+
+// Filter selects the events of a subscription. Empty fields match all.
+type Filter struct {
+	Entities   []string
+	Operations []string
+	IDs        []string
+}
+
+// Validate checks the entities and operations are known
+func (f Filter) Validate() error {
+	for _, entity := range f.Entities {
+		if _, ok := collections[entity]; !ok {
+			return fmt.Errorf("unknown entity %q, must be one of %s, %s, %s or %s", entity, EntityModel, EntityContext, EntityData, EntityExecution)
+		}
+	}
+	for _, op := range f.Operations {
+		if !contains(operations, op) {
+			return fmt.Errorf("unknown operation %q, must be one of %s", op, strings.Join(operations, ", "))
+		}
+	}
+	return nil
+}
+
+// entities returns the entities to watch, sorted
+func (f Filter) entities() []string {
+	if len(f.Entities) > 0 {
+		return f.Entities
+	}
+	return []string{EntityContext, EntityData, EntityExecution, EntityModel}
+}
+
+func (f Filter) matches(operation, id string) bool {
+	return (len(f.Operations) == 0 || contains(f.Operations, operation)) &&
+		(len(f.IDs) == 0 || contains(f.IDs, id))
+}
+
+// Config controls the polling fallback
+type Config struct {
+	PollInterval time.Duration // how often collections are polled without change streams, 2s by default
+}
+
+// Feed publishes the changes of the entities stored in a database. It
+// follows MongoDB change streams, and polls the collections when the
+// server does not support them, as standalone servers do.
+type Feed struct {
+	cfg Config
+	db  *mongo.Database
+
+	mu      sync.Mutex
+	polling bool // whether change streams were found unsupported
+	closed  chan struct{}
+	once    sync.Once
+}
+
+// NewFeed creates a Feed
+func NewFeed(db *mongo.Database, cfg Config) *Feed {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	return &Feed{cfg: cfg, db: db, closed: make(chan struct{})}
+}
+
+// Subscribe calls fn with every event matching filter until ctx is done,
+// the feed is closed or fn returns an error. Events follow the one
+// carrying token, or start from now when it is empty. A subscription ended
+// by Close returns nil.
+func (f *Feed) Subscribe(ctx context.Context, filter Filter, token string, fn func(Event) error) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-f.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	f.mu.Lock()
+	polling := f.polling
+	f.mu.Unlock()
+
+	var err error
+	if !polling {
+		err = f.watch(ctx, filter, token, fn)
+		if errors.Is(err, errUnsupported) {
+			slog.Info("Change streams are not supported, polling for changes", "interval", f.cfg.PollInterval)
+			f.mu.Lock()
+			f.polling = true
+			f.mu.Unlock()
+			polling = true
+		}
+	}
+	if polling {
+		err = f.poll(ctx, filter, token, fn)
+	}
+
+	select {
+	case <-f.closed:
+		return nil
+	default:
+		return err
+	}
+}
+
+// Close ends every subscription, such as when the server shuts down
+func (f *Feed) Close() {
+	f.once.Do(func() { close(f.closed) })
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
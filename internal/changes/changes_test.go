@@ -0,0 +1,85 @@
+package changes
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testMongoURIEnv names the environment variable holding the URI of a
+// MongoDB server for the feed tests. They are skipped without it.
+const testMongoURIEnv = "MCP_TEST_MONGODB_URI"
+
+// testDatabase returns a scratch database, dropped when the test ends
+func testDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv(testMongoURIEnv)
+	if uri == "" {
+		t.Skipf("%s not set", testMongoURIEnv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	db := client.Database("mcp_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		db.Drop(ctx)
+		client.Disconnect(ctx)
+	})
+	return db
+}
+
+func TestFilterValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantErr bool
+	}{
+		{"empty", Filter{}, false},
+		{"known", Filter{Entities: []string{EntityModel, EntityExecution}, Operations: []string{OperationDeleted}}, false},
+		{"unknown entity", Filter{Entities: []string{"models"}}, true},
+		{"unknown operation", Filter{Operations: []string{"insert"}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.filter.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	const id = "64b7f0c2e4b0a1a2b3c4d5e6"
+	tests := []struct {
+		name      string
+		filter    Filter
+		operation string
+		want      bool
+	}{
+		{"empty", Filter{}, OperationUpdated, true},
+		{"operation", Filter{Operations: []string{OperationCreated, OperationUpdated}}, OperationUpdated, true},
+		{"other operation", Filter{Operations: []string{OperationCreated}}, OperationDeleted, false},
+		{"id", Filter{IDs: []string{"other", id}}, OperationCreated, true},
+		{"other id", Filter{IDs: []string{"other"}}, OperationCreated, false},
+		{"operation and other id", Filter{Operations: []string{OperationCreated}, IDs: []string{"other"}}, OperationCreated, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.matches(tt.operation, id); got != tt.want {
+			t.Errorf("%s: matches(%q) = %v, want %v", tt.name, tt.operation, got, tt.want)
+		}
+	}
+
+	// All entities are watched unless some are chosen
+	if got := (Filter{}).entities(); len(got) != len(collections) {
+		t.Errorf("entities() = %v, want all %d", got, len(collections))
+	}
+}
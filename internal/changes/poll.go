@@ -0,0 +1,181 @@
+package changes
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// pollState is what polling knows of a collection
+type pollState struct {
+	entity string
+	since  time.Time       // updated_at of the last change seen
+	sent   map[string]bool // IDs of the changes seen at since
+	known  map[string]bool // IDs of the entities present at the last poll
+}
+
+// poll finds changes by querying the collections every poll interval:
+// entities whose updated_at moved were created or updated, and those whose
+// ID disappeared were deleted. Deletions are only seen while subscribed,
+// and entities created and deleted between two polls are missed. Changes
+// made at the time of a resume token are sent again after resuming.
+func (f *Feed) poll(ctx context.Context, filter Filter, token string, fn func(Event) error) error {
+	since := time.Now()
+	if token != "" {
+		if !strings.HasPrefix(token, pollTokenPrefix) {
+			// Change stream tokens cannot be mapped to a time
+			return ErrInvalidToken
+		}
+		var err error
+		if since, err = parsePollToken(token); err != nil {
+			return err
+		}
+	}
+
+	var states []*pollState
+	for _, entity := range filter.entities() {
+		state := &pollState{entity: entity, since: since, sent: make(map[string]bool)}
+		known, err := f.ids(ctx, entity, filter)
+		if err != nil {
+			return err
+		}
+		state.known = known
+		states = append(states, state)
+	}
+
+	ticker := time.NewTicker(f.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		for _, state := range states {
+			if err := f.pollCollection(ctx, state, filter, fn); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollCollection sends the changes of a collection since its last poll.
+// Documents updated in the same millisecond as the last change seen are
+// queried again, as they may have been written after that poll, and
+// skipped if already sent.
+func (f *Feed) pollCollection(ctx context.Context, state *pollState, filter Filter, fn func(Event) error) error {
+	collection := f.db.Collection(collections[state.entity])
+	query := bson.M{"updated_at": bson.M{"$gte": state.since}}
+	if len(filter.IDs) > 0 {
+		query["_id"] = bson.M{"$in": objectIDs(filter.IDs)}
+	}
+
+	cursor, err := collection.Find(ctx, query, options.Find().SetSort(bson.M{"updated_at": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var stamps struct {
+			ID        bson.RawValue `bson:"_id"`
+			CreatedAt time.Time     `bson:"created_at"`
+			UpdatedAt time.Time     `bson:"updated_at"`
+		}
+		if err := cursor.Decode(&stamps); err != nil {
+			return err
+		}
+
+		id := idString(stamps.ID)
+		if stamps.UpdatedAt.Equal(state.since) && state.sent[id] {
+			continue
+		}
+		operation := OperationUpdated
+		if !state.known[id] || stamps.CreatedAt.After(state.since) {
+			operation = OperationCreated
+		}
+		state.known[id] = true
+		if !stamps.UpdatedAt.Equal(state.since) {
+			state.since = stamps.UpdatedAt
+			state.sent = make(map[string]bool)
+		}
+		state.sent[id] = true
+
+		if !filter.matches(operation, id) {
+			continue
+		}
+		if err := fn(Event{
+			Entity:    state.entity,
+			Operation: operation,
+			ID:        id,
+			Document:  append(bson.Raw(nil), cursor.Current...),
+			Time:      stamps.UpdatedAt,
+			Token:     pollToken(state.since),
+		}); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	if len(filter.Operations) > 0 && !contains(filter.Operations, OperationDeleted) {
+		return nil
+	}
+	present, err := f.ids(ctx, state.entity, filter)
+	if err != nil {
+		return err
+	}
+	for id := range state.known {
+		if present[id] {
+			continue
+		}
+		delete(state.known, id)
+		if err := fn(Event{
+			Entity:    state.entity,
+			Operation: OperationDeleted,
+			ID:        id,
+			Time:      time.Now(),
+			Token:     pollToken(state.since),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ids returns the IDs of an entity's documents matching filter
+func (f *Feed) ids(ctx context.Context, entity string, filter Filter) (map[string]bool, error) {
+	query := bson.M{}
+	if len(filter.IDs) > 0 {
+		query["_id"] = bson.M{"$in": objectIDs(filter.IDs)}
+	}
+	cursor, err := f.db.Collection(collections[entity]).Find(ctx, query, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := make(map[string]bool)
+	for cursor.Next(ctx) {
+		ids[idString(cursor.Current.Lookup("_id"))] = true
+	}
+	return ids, cursor.Err()
+}
+
+func pollToken(since time.Time) string {
+	return pollTokenPrefix + strconv.FormatInt(since.UnixNano(), 10)
+}
+
+func parsePollToken(token string) (time.Time, error) {
+	nanos, err := strconv.ParseInt(strings.TrimPrefix(token, pollTokenPrefix), 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidToken
+	}
+	return time.Unix(0, nanos), nil
+}
@@ -0,0 +1,147 @@
+package changes
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPollToken(t *testing.T) {
+	since := time.Date(2024, 3, 1, 12, 0, 0, 123000000, time.UTC)
+	got, err := parsePollToken(pollToken(since))
+	if err != nil || !got.Equal(since) {
+		t.Errorf("parsePollToken(pollToken(%s)) = %s, %v", since, got, err)
+	}
+
+	for _, token := range []string{"poll:", "poll:yesterday", "poll:1.5"} {
+		if _, err := parsePollToken(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("parsePollToken(%q) = %v, want ErrInvalidToken", token, err)
+		}
+	}
+
+	// Change stream tokens do not map to a time to poll from
+	f := &Feed{}
+	if err := f.poll(context.Background(), Filter{}, streamTokenPrefix+"AAAA", nil); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("poll with a change stream token = %v, want ErrInvalidToken", err)
+	}
+}
+
+// pollRecorder records the events of polls
+type pollRecorder struct {
+	events []Event
+}
+
+func (r *pollRecorder) record(e Event) error {
+	r.events = append(r.events, e)
+	return nil
+}
+
+// take returns the events recorded since the last take, as operation and
+// ID pairs
+func (r *pollRecorder) take() []string {
+	var got []string
+	for _, e := range r.events {
+		got = append(got, e.Operation+" "+e.ID)
+	}
+	r.events = nil
+	return got
+}
+
+func expectEvents(t *testing.T, step string, got []string, want ...string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: events %v, want %v", step, got, want)
+	}
+}
+
+func TestPollCollection(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+	models := db.Collection(collections[EntityModel])
+	f := NewFeed(db, Config{})
+
+	start := time.Now().UTC().Truncate(time.Millisecond)
+	t0, t1 := start.Add(time.Second), start.Add(2*time.Second)
+	insert := func(id primitive.ObjectID, at time.Time) {
+		t.Helper()
+		if _, err := models.InsertOne(ctx, bson.M{"_id": id, "created_at": at, "updated_at": at}); err != nil {
+			t.Fatalf("InsertOne: %v", err)
+		}
+	}
+	a, b, c := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	state := &pollState{entity: EntityModel, since: start, sent: make(map[string]bool), known: make(map[string]bool)}
+	var r pollRecorder
+	poll := func() []string {
+		t.Helper()
+		if err := f.pollCollection(ctx, state, Filter{}, r.record); err != nil {
+			t.Fatalf("pollCollection: %v", err)
+		}
+		return r.take()
+	}
+
+	insert(a, t0)
+	expectEvents(t, "insert", poll(), "created "+a.Hex())
+	expectEvents(t, "no change", poll())
+
+	// Written in the millisecond of the last change seen, after that poll
+	insert(b, t0)
+	expectEvents(t, "same millisecond", poll(), "created "+b.Hex())
+
+	if _, err := models.UpdateOne(ctx, bson.M{"_id": a}, bson.M{"$set": bson.M{"updated_at": t1}}); err != nil {
+		t.Fatalf("UpdateOne: %v", err)
+	}
+	expectEvents(t, "update", poll(), "updated "+a.Hex())
+	if !state.since.Equal(t1) {
+		t.Errorf("cursor at %s after the update, want %s", state.since, t1)
+	}
+
+	if _, err := models.DeleteOne(ctx, bson.M{"_id": b}); err != nil {
+		t.Fatalf("DeleteOne: %v", err)
+	}
+	expectEvents(t, "delete", poll(), "deleted "+b.Hex())
+
+	// Filters apply to the events, not to the cursor
+	insert(c, t1)
+	if err := f.pollCollection(ctx, state, Filter{Operations: []string{OperationUpdated}}, r.record); err != nil {
+		t.Fatalf("pollCollection: %v", err)
+	}
+	expectEvents(t, "filtered", r.take())
+	expectEvents(t, "after the filtered poll", poll())
+}
+
+func TestPollResume(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+	models := db.Collection(collections[EntityModel])
+	f := NewFeed(db, Config{PollInterval: 10 * time.Millisecond})
+
+	t0 := time.Now().UTC().Truncate(time.Millisecond).Add(-time.Minute)
+	before, at, after := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	for id, updated := range map[primitive.ObjectID]time.Time{before: t0.Add(-time.Second), at: t0, after: t0.Add(time.Second)} {
+		if _, err := models.InsertOne(ctx, bson.M{"_id": id, "created_at": t0.Add(-time.Hour), "updated_at": updated}); err != nil {
+			t.Fatalf("InsertOne: %v", err)
+		}
+	}
+
+	// Resuming from a token sends the changes made at and after its time,
+	// the ones at its time again, as it cannot tell which were sent
+	errDone := errors.New("done")
+	var r pollRecorder
+	err := f.poll(ctx, Filter{Entities: []string{EntityModel}}, pollToken(t0), func(e Event) error {
+		r.record(e)
+		if len(r.events) == 2 {
+			return errDone
+		}
+		return nil
+	})
+	if !errors.Is(err, errDone) {
+		t.Fatalf("poll = %v, want it ended by the subscriber", err)
+	}
+	expectEvents(t, "resume", r.take(), "updated "+at.Hex(), "updated "+after.Hex())
+}
@@ -0,0 +1,191 @@
+package changes
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Prefixes of the resume tokens of change streams and of polling
+const (
+	streamTokenPrefix = "cs:"
+	pollTokenPrefix   = "poll:"
+)
+
+// errUnsupported is returned by watch when the server has no change streams
+var errUnsupported = errors.New("change streams are not supported")
+
+// changeStreamsUnsupported is the code of the error returned by standalone
+// servers when a change stream is opened
+const changeStreamsUnsupported = 40573
+
+// streamOperations maps operations to the change events reporting them
+var streamOperations = map[string][]string{
+	OperationCreated: {"insert"},
+	OperationUpdated: {"update", "replace"},
+	OperationDeleted: {"delete"},
+}
+
+// change is a change stream event
+type change struct {
+	OperationType string              `bson:"operationType"`
+	ClusterTime   primitive.Timestamp `bson:"clusterTime"`
+	NS            struct {
+		Coll string `bson:"coll"`
+	} `bson:"ns"`
+	DocumentKey struct {
+		ID bson.RawValue `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument      bson.Raw `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.Raw `bson:"updatedFields"`
+	} `bson:"updateDescription"`
+}
+
+// watch follows a change stream of the database
+func (f *Feed) watch(ctx context.Context, filter Filter, token string, fn func(Event) error) error {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	switch {
+	case token == "":
+	case strings.HasPrefix(token, streamTokenPrefix):
+		raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, streamTokenPrefix))
+		if err != nil {
+			return ErrInvalidToken
+		}
+		opts.SetResumeAfter(bson.Raw(raw))
+	case strings.HasPrefix(token, pollTokenPrefix):
+		// Issued while polling: resume from the same time
+		since, err := parsePollToken(token)
+		if err != nil {
+			return err
+		}
+		opts.SetStartAtOperationTime(&primitive.Timestamp{T: uint32(since.Unix())})
+	default:
+		return ErrInvalidToken
+	}
+
+	stream, err := f.db.Watch(ctx, f.pipeline(filter), opts)
+	if err != nil {
+		var serverErr mongo.ServerError
+		if errors.As(err, &serverErr) && serverErr.HasErrorCode(changeStreamsUnsupported) {
+			return errUnsupported
+		}
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	entities := make(map[string]string, len(collections))
+	for entity, collection := range collections {
+		entities[collection] = entity
+	}
+
+	for stream.Next(ctx) {
+		var c change
+		if err := stream.Decode(&c); err != nil {
+			return err
+		}
+		if c.OperationType == "update" && leaseRenewal(c.UpdateDescription.UpdatedFields) {
+			continue
+		}
+
+		event := Event{
+			Entity:   entities[c.NS.Coll],
+			ID:       idString(c.DocumentKey.ID),
+			Document: c.FullDocument,
+			Time:     time.Unix(int64(c.ClusterTime.T), 0),
+			Token:    streamTokenPrefix + base64.RawURLEncoding.EncodeToString(stream.ResumeToken()),
+		}
+		for op, types := range streamOperations {
+			if contains(types, c.OperationType) {
+				event.Operation = op
+			}
+		}
+		if event.Entity == "" || event.Operation == "" {
+			continue
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := stream.Err(); err != nil {
+		return err
+	}
+	// Closed by the server, such as when the database is dropped
+	return errors.New("change stream ended")
+}
+
+// pipeline matches the change events of filter's entities, operations and
+// IDs
+func (f *Feed) pipeline(filter Filter) mongo.Pipeline {
+	var colls []string
+	for _, entity := range filter.entities() {
+		colls = append(colls, collections[entity])
+	}
+	ops := filter.Operations
+	if len(ops) == 0 {
+		ops = operations
+	}
+	var types []string
+	for _, op := range ops {
+		types = append(types, streamOperations[op]...)
+	}
+
+	match := bson.M{
+		"ns.coll":       bson.M{"$in": colls},
+		"operationType": bson.M{"$in": types},
+	}
+	if len(filter.IDs) > 0 {
+		match["documentKey._id"] = bson.M{"$in": objectIDs(filter.IDs)}
+	}
+	return mongo.Pipeline{{{Key: "$match", Value: match}}}
+}
+
+// leaseRenewal reports whether an update only extended an execution's
+// lease, which happens every few seconds while it runs and is not worth
+// publishing
+func leaseRenewal(updated bson.Raw) bool {
+	elements, err := updated.Elements()
+	if err != nil || len(elements) == 0 {
+		return false
+	}
+	for _, e := range elements {
+		if e.Key() != "lease_expires_at" {
+			return false
+		}
+	}
+	return true
+}
+
+// objectIDs converts the IDs that are object IDs, keeping the others as is
+func objectIDs(ids []string) []interface{} {
+	values := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			values = append(values, objectID)
+		} else {
+			values = append(values, id)
+		}
+	}
+	return values
+}
+
+func idString(v bson.RawValue) string {
+	if objectID, ok := v.ObjectIDOK(); ok {
+		return objectID.Hex()
+	}
+	if s, ok := v.StringValueOK(); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,73 @@
+package changes
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLeaseRenewal(t *testing.T) {
+	tests := []struct {
+		name    string
+		updated bson.D
+		want    bool
+	}{
+		{"lease only", bson.D{{Key: "lease_expires_at", Value: primitive.NewDateTimeFromTime(time.Now())}}, true},
+		{"lease and status", bson.D{{Key: "lease_expires_at", Value: 1}, {Key: "status", Value: "completed"}}, false},
+		{"status", bson.D{{Key: "status", Value: "running"}}, false},
+		{"nothing", bson.D{}, false},
+	}
+	for _, tt := range tests {
+		raw, err := bson.Marshal(tt.updated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := leaseRenewal(raw); got != tt.want {
+			t.Errorf("%s: leaseRenewal() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestObjectIDs(t *testing.T) {
+	oid := primitive.NewObjectID()
+	got := objectIDs([]string{oid.Hex(), "custom-id"})
+	if len(got) != 2 || got[0] != oid || got[1] != "custom-id" {
+		t.Errorf("objectIDs() = %v, want [%v custom-id]", got, oid)
+	}
+
+	// idString reverses it, so events carry the IDs subscribers filter on
+	for _, value := range []interface{}{oid, "custom-id"} {
+		typ, data, err := bson.MarshalValue(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := fmt.Sprint(value)
+		if value == oid {
+			want = oid.Hex()
+		}
+		if got := idString(bson.RawValue{Type: typ, Value: data}); got != want {
+			t.Errorf("idString(%v) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	f := &Feed{}
+	pipeline := f.pipeline(Filter{Entities: []string{EntityExecution}, Operations: []string{OperationUpdated}})
+	match := pipeline[0][0].Value.(bson.M)
+
+	colls := match["ns.coll"].(bson.M)["$in"].([]string)
+	if len(colls) != 1 || colls[0] != "executions" {
+		t.Errorf("pipeline watches %v, want executions", colls)
+	}
+	types := match["operationType"].(bson.M)["$in"].([]string)
+	if len(types) != 2 || types[0] != "update" || types[1] != "replace" {
+		t.Errorf("pipeline matches %v, want update and replace", types)
+	}
+	if _, ok := match["documentKey._id"]; ok {
+		t.Error("pipeline filters IDs without any chosen")
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"encoding/json"
 	"os"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
 )
 
 type Config struct {
@@ -26,6 +28,67 @@ type Config struct {
 			Data       string `json:"data"`
 		} `json:"collections"`
 	} `json:"database"`
+	Performance struct {
+		MaxConcurrentRequests int            `json:"maxConcurrentRequests"`
+		TimeoutSeconds        int            `json:"timeoutSeconds"`
+		RetryAttempts         int            `json:"retryAttempts"`
+		ProviderConcurrency   map[string]int `json:"providerConcurrency"`
+	} `json:"performance"`
+	Queue struct {
+		LeaseSeconds        int            `json:"leaseSeconds"`
+		PollIntervalSeconds int            `json:"pollIntervalSeconds"`
+		MaxClaims           int            `json:"maxClaims"`
+		WorkerID            string         `json:"workerId"`
+		Weights             map[string]int `json:"weights"`
+	} `json:"queue"`
+	Cache struct {
+		Enabled       bool `json:"enabled"`
+		MemoryEntries int  `json:"memoryEntries"`
+		TTLSeconds    int  `json:"ttlSeconds"`
+	} `json:"cache"`
+	Scheduler struct {
+		IntervalSeconds int `json:"intervalSeconds"`
+	} `json:"scheduler"`
+	ModelHealth struct {
+		Enabled          bool `json:"enabled"`
+		IntervalSeconds  int  `json:"intervalSeconds"`
+		TimeoutSeconds   int  `json:"timeoutSeconds"`
+		FailureThreshold int  `json:"failureThreshold"`
+		RetentionHours   int  `json:"retentionHours"`
+		Concurrency      int  `json:"concurrency"`
+	} `json:"modelHealth"`
+	ModelSchemas map[string]model.Schema `json:"modelSchemas"`
+	Metrics      struct {
+		Enabled bool   `json:"enabled"`
+		Address string `json:"address"`
+		Path    string `json:"path"`
+	} `json:"metrics"`
+	Tracing struct {
+		Exporter    string  `json:"exporter"`
+		Endpoint    string  `json:"endpoint"`
+		Insecure    bool    `json:"insecure"`
+		ServiceName string  `json:"serviceName"`
+		SampleRatio float64 `json:"sampleRatio"`
+	} `json:"tracing"`
+	HealthCheck struct {
+		IntervalSeconds int `json:"intervalSeconds"`
+		TimeoutSeconds  int `json:"timeoutSeconds"`
+	} `json:"healthCheck"`
+	Secrets struct {
+		KeyFile string `json:"keyFile"`
+	} `json:"secrets"`
+	Logging struct {
+		Level  string   `json:"level"`
+		Format string   `json:"format"`
+		Redact []string `json:"redact"`
+	} `json:"logging"`
+	Changes struct {
+		PollIntervalSeconds int `json:"pollIntervalSeconds"`
+	} `json:"changes"`
+	Shutdown struct {
+		GracePeriodSeconds  int `json:"gracePeriodSeconds"`
+		CloseTimeoutSeconds int `json:"closeTimeoutSeconds"`
+	} `json:"shutdown"`
 }
 
 func LoadConfig(path string) (*Config, error) {
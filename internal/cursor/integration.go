@@ -4,17 +4,30 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/DavutcanJ/mongo-mcp-server/internal/healthcheck"
 	"github.com/DavutcanJ/mongo-mcp-server/pkg/proto"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Integration represents the Cursor MCP integration
 type Integration struct {
 	client proto.MCPServiceClient
+	health healthpb.HealthClient
 	config *Config
+	in     io.Reader
+	out    io.Writer
 }
 
 // Config represents the Cursor MCP configuration
@@ -30,9 +43,28 @@ type Config struct {
 	} `json:"commands"`
 }
 
+// apiKey sends the caller's API key with every request, so the server can
+// attribute usage to it
+type apiKey string
+
+func (k apiKey) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"x-api-key": string(k)}, nil
+}
+
+func (k apiKey) RequireTransportSecurity() bool {
+	return false
+}
+
 // NewIntegration creates a new Cursor MCP integration
 func NewIntegration(addr string) (*Integration, error) {
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	opts := []grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	if key := os.Getenv("MCP_API_KEY"); key != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(apiKey(key)))
+	}
+	conn, err := grpc.Dial(addr, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial server: %v", err)
 	}
@@ -45,7 +77,10 @@ func NewIntegration(addr string) (*Integration, error) {
 
 	return &Integration{
 		client: client,
+		health: healthpb.NewHealthClient(conn),
 		config: config,
+		in:     os.Stdin,
+		out:    os.Stdout,
 	}, nil
 }
 
@@ -56,12 +91,40 @@ func (i *Integration) HandleCommand(ctx context.Context, command string, args []
 		return i.handleModelCommand(ctx, args)
 	case "context":
 		return i.handleContextCommand(ctx, args)
+	case "protocol":
+		return i.handleProtocolCommand(ctx, args)
 	case "execute":
 		return i.handleExecuteCommand(ctx, args)
 	case "status":
 		return i.handleStatusCommand(ctx, args)
+	case "watch":
+		return i.handleWatchCommand(ctx, args)
+	case "cancel":
+		return i.handleCancelCommand(ctx, args)
+	case "executions":
+		return i.handleExecutionsCommand(ctx, args)
+	case "queue":
+		return i.handleQueueCommand(ctx, args)
+	case "schedule":
+		return i.handleScheduleCommand(ctx, args)
+	case "batch":
+		return i.handleBatchCommand(ctx, args)
+	case "cache":
+		return i.handleCacheCommand(ctx, args)
+	case "usage":
+		return i.handleUsageCommand(ctx, args)
+	case "tokens":
+		return i.handleTokensCommand(ctx, args)
 	case "data":
 		return i.handleDataCommand(ctx, args)
+	case "secret":
+		return i.handleSecretCommand(ctx, args)
+	case "health":
+		return i.handleHealthCommand(ctx, args)
+	case "log-level":
+		return i.handleLogLevelCommand(ctx, args)
+	case "subscribe":
+		return i.handleSubscribeCommand(ctx, args)
 	default:
 		return "", fmt.Errorf("unknown command: %s", command)
 	}
@@ -75,22 +138,22 @@ func (i *Integration) handleModelCommand(ctx context.Context, args []string) (st
 
 	switch args[0] {
 	case "create":
-		if len(args) < 4 {
-			return "", fmt.Errorf("create model requires name, type, and parameters")
+		if len(args) < 3 {
+			return "", fmt.Errorf("create model requires name and type")
 		}
 
-		params := make(map[string]string)
-		if len(args) > 4 {
-			err := json.Unmarshal([]byte(args[4]), &params)
-			if err != nil {
-				return "", fmt.Errorf("invalid parameters JSON: %v", err)
+		var params *structpb.Struct
+		if len(args) > 3 {
+			var err error
+			if params, err = parseParams(args[3]); err != nil {
+				return "", err
 			}
 		}
 
 		resp, err := i.client.CreateModel(ctx, &proto.Model{
-			Name:       args[1],
-			Type:       args[2],
-			Parameters: params,
+			Name:   args[1],
+			Type:   args[2],
+			Params: params,
 		})
 		if err != nil {
 			return "", err
@@ -126,6 +189,129 @@ func (i *Integration) handleModelCommand(ctx context.Context, args []string) (st
 		}
 		return formatModels(resp.Models), nil
 
+	case "version":
+		if len(args) < 3 {
+			return "", fmt.Errorf("model version requires id and type")
+		}
+
+		var params *structpb.Struct
+		if len(args) > 3 {
+			var err error
+			if params, err = parseParams(args[3]); err != nil {
+				return "", err
+			}
+		}
+
+		resp, err := i.client.CreateModelVersion(ctx, &proto.Model{
+			Id:     args[1],
+			Type:   args[2],
+			Params: params,
+		})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return fmt.Sprintf("Model %s is now at version %d", resp.Model.Id, resp.Model.Version), nil
+
+	case "versions":
+		if len(args) < 2 {
+			return "", fmt.Errorf("model versions requires id")
+		}
+		resp, err := i.client.ListModelVersions(ctx, &proto.ModelRequest{Id: args[1]})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatModels(resp.Models), nil
+
+	case "alias":
+		if len(args) < 3 {
+			return "", fmt.Errorf("model alias requires id and alias")
+		}
+		req := &proto.ModelAlias{ModelId: args[1], Alias: args[2]}
+		if len(args) > 3 {
+			version, err := strconv.Atoi(strings.TrimPrefix(args[3], "v"))
+			if err != nil {
+				return "", fmt.Errorf("invalid version %q: %v", args[3], err)
+			}
+			req.Version = int32(version)
+		}
+
+		resp, err := i.client.SetModelAlias(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return fmt.Sprintf("%s -> version %d", resp.Alias.Ref, resp.Alias.Version), nil
+
+	case "aliases":
+		if len(args) < 2 {
+			return "", fmt.Errorf("model aliases requires id")
+		}
+		resp, err := i.client.ListModelAliases(ctx, &proto.ModelRequest{Id: args[1]})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatModelAliases(resp.Aliases), nil
+
+	case "unalias":
+		if len(args) < 3 {
+			return "", fmt.Errorf("model unalias requires id and alias")
+		}
+		resp, err := i.client.DeleteModelAlias(ctx, &proto.ModelAlias{ModelId: args[1], Alias: args[2]})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return fmt.Sprintf("Alias %s removed", args[2]), nil
+
+	case "health":
+		req := &proto.ModelHealthRequest{}
+		rest, history := extractOption(args[1:], "--history")
+		if history != "" {
+			n, err := strconv.Atoi(history)
+			if err != nil {
+				return "", fmt.Errorf("invalid history %q: %v", history, err)
+			}
+			req.History = int32(n)
+		}
+		if len(rest) > 0 {
+			req.ModelId = rest[0]
+		}
+
+		resp, err := i.client.GetModelHealth(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatModelHealth(resp.Models), nil
+
+	case "schema":
+		if len(args) < 2 {
+			return "", fmt.Errorf("model schema requires type")
+		}
+		resp, err := i.client.GetModelSchema(ctx, &proto.ModelSchemaRequest{Type: args[1]})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatModelSchema(resp), nil
+
 	default:
 		return "", fmt.Errorf("unknown model subcommand: %s", args[0])
 	}
@@ -190,104 +376,93 @@ func (i *Integration) handleContextCommand(ctx context.Context, args []string) (
 		}
 		return formatContexts(resp.Contexts), nil
 
-	default:
-		return "", fmt.Errorf("unknown context subcommand: %s", args[0])
-	}
-}
-
-// handleExecuteCommand handles protocol execution commands
-func (i *Integration) handleExecuteCommand(ctx context.Context, args []string) (string, error) {
-	if len(args) < 3 {
-		return "", fmt.Errorf("execute command requires model_id, context_id and input")
-	}
+	case "link", "unlink":
+		if len(args) < 3 {
+			return "", fmt.Errorf("%s context requires context_id and model_id", args[0])
+		}
+		req := &proto.ContextModelRequest{ContextId: args[1], ModelId: args[2]}
 
-	params := make(map[string]string)
-	if len(args) > 3 {
-		err := json.Unmarshal([]byte(args[3]), &params)
+		var resp *proto.ContextResponse
+		var err error
+		if args[0] == "link" {
+			resp, err = i.client.LinkContextModel(ctx, req)
+		} else {
+			resp, err = i.client.UnlinkContextModel(ctx, req)
+		}
 		if err != nil {
-			return "", fmt.Errorf("invalid parameters JSON: %v", err)
+			return "", err
 		}
-	}
-
-	resp, err := i.client.ExecuteProtocol(ctx, &proto.Protocol{
-		ModelId:    args[0],
-		ContextId:  args[1],
-		Input:      args[2],
-		Parameters: params,
-	})
-	if err != nil {
-		return "", err
-	}
-	if resp.Error != "" {
-		return "", fmt.Errorf(resp.Error)
-	}
-	return fmt.Sprintf("Protocol execution started: %s", resp.Id), nil
-}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatContext(resp.Context), nil
 
-// handleStatusCommand handles protocol status commands
-func (i *Integration) handleStatusCommand(ctx context.Context, args []string) (string, error) {
-	if len(args) < 1 {
-		return "", fmt.Errorf("status command requires execution_id")
-	}
+	case "for-model":
+		if len(args) < 2 {
+			return "", fmt.Errorf("for-model requires model_id")
+		}
+		resp, err := i.client.ListContextsForModel(ctx, &proto.ModelContextsRequest{
+			ModelId:  args[1],
+			PageSize: 10,
+		})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatContexts(resp.Contexts), nil
 
-	resp, err := i.client.GetProtocolStatus(ctx, &proto.ProtocolRequest{Id: args[0]})
-	if err != nil {
-		return "", err
-	}
-	if resp.Error != "" {
-		return "", fmt.Errorf(resp.Error)
+	default:
+		return "", fmt.Errorf("unknown context subcommand: %s", args[0])
 	}
-	return fmt.Sprintf("Status: %s", resp.Status), nil
 }
 
-// handleDataCommand handles data-related commands
-func (i *Integration) handleDataCommand(ctx context.Context, args []string) (string, error) {
+// handleProtocolCommand handles protocol definition commands
+func (i *Integration) handleProtocolCommand(ctx context.Context, args []string) (string, error) {
 	if len(args) < 1 {
-		return "", fmt.Errorf("data command requires subcommand")
+		return "", fmt.Errorf("protocol command requires subcommand")
 	}
 
 	switch args[0] {
-	case "add":
-		if len(args) < 3 {
-			return "", fmt.Errorf("add data requires type and content")
+	case "create", "update":
+		def, err := parseProtocolDefinition(args)
+		if err != nil {
+			return "", err
 		}
 
-		metadata := make(map[string]string)
-		if len(args) > 3 {
-			err := json.Unmarshal([]byte(args[3]), &metadata)
-			if err != nil {
-				return "", fmt.Errorf("invalid metadata JSON: %v", err)
-			}
+		var resp *proto.ProtocolDefinitionResponse
+		if args[0] == "create" {
+			resp, err = i.client.CreateProtocol(ctx, def)
+		} else {
+			resp, err = i.client.UpdateProtocol(ctx, def)
 		}
-
-		resp, err := i.client.AddData(ctx, &proto.Data{
-			Type:     args[1],
-			Content:  []byte(args[2]),
-			Metadata: metadata,
-		})
 		if err != nil {
 			return "", err
 		}
 		if resp.Error != "" {
 			return "", fmt.Errorf(resp.Error)
 		}
-		return fmt.Sprintf("Data added: %s", resp.Data.Id), nil
+		if args[0] == "create" {
+			return fmt.Sprintf("Protocol created: %s", resp.Protocol.Id), nil
+		}
+		return fmt.Sprintf("Protocol updated: %s", resp.Protocol.Id), nil
 
 	case "get":
 		if len(args) < 2 {
-			return "", fmt.Errorf("get data requires id")
+			return "", fmt.Errorf("get protocol requires id")
 		}
-		resp, err := i.client.GetData(ctx, &proto.DataRequest{Id: args[1]})
+		resp, err := i.client.GetProtocol(ctx, &proto.ProtocolDefinitionRequest{Id: args[1]})
 		if err != nil {
 			return "", err
 		}
 		if resp.Error != "" {
 			return "", fmt.Errorf(resp.Error)
 		}
-		return formatData(resp.Data), nil
+		return formatProtocol(resp.Protocol), nil
 
 	case "list":
-		resp, err := i.client.ListData(ctx, &proto.ListRequest{
+		resp, err := i.client.ListProtocols(ctx, &proto.ListRequest{
 			PageSize: 10,
 			Filters:  make(map[string]string),
 		})
@@ -297,78 +472,1256 @@ func (i *Integration) handleDataCommand(ctx context.Context, args []string) (str
 		if resp.Error != "" {
 			return "", fmt.Errorf(resp.Error)
 		}
-		return formatDataList(resp.Data), nil
+		return formatProtocols(resp.Protocols), nil
 
 	case "delete":
 		if len(args) < 2 {
-			return "", fmt.Errorf("delete data requires id")
+			return "", fmt.Errorf("delete protocol requires id")
 		}
-		resp, err := i.client.DeleteData(ctx, &proto.DataRequest{Id: args[1]})
+		resp, err := i.client.DeleteProtocol(ctx, &proto.ProtocolDefinitionRequest{Id: args[1]})
 		if err != nil {
 			return "", err
 		}
 		if resp.Error != "" {
 			return "", fmt.Errorf(resp.Error)
 		}
-		return "Data deleted successfully", nil
+		return "Protocol deleted successfully", nil
 
 	default:
-		return "", fmt.Errorf("unknown data subcommand: %s", args[0])
+		return "", fmt.Errorf("unknown protocol subcommand: %s", args[0])
 	}
 }
 
-// loadConfig loads the Cursor MCP configuration
-func loadConfig() (*Config, error) {
-	configPath := filepath.Join("configs", "cursor.json")
-	data, err := os.ReadFile(configPath)
+// parseProtocolDefinition parses the arguments of "protocol create <name>
+// <type> [steps] [parameters]" and "protocol update <id> <name> <type>
+// [steps] [parameters]"
+func parseProtocolDefinition(args []string) (*proto.ProtocolDefinition, error) {
+	def := &proto.ProtocolDefinition{}
+	if args[0] == "update" {
+		if len(args) < 4 {
+			return nil, fmt.Errorf("update protocol requires id, name and type")
+		}
+		def.Id = args[1]
+		args = args[1:]
+	} else if len(args) < 3 {
+		return nil, fmt.Errorf("create protocol requires name and type")
+	}
+
+	def.Name = args[1]
+	def.Type = args[2]
+	if len(args) > 3 {
+		if err := json.Unmarshal([]byte(args[3]), &def.Steps); err != nil {
+			return nil, fmt.Errorf("invalid steps JSON: %v", err)
+		}
+	}
+	if len(args) > 4 {
+		if err := json.Unmarshal([]byte(args[4]), &def.Parameters); err != nil {
+			return nil, fmt.Errorf("invalid parameters JSON: %v", err)
+		}
+	}
+	return def, nil
+}
+
+// handleExecuteCommand handles protocol execution commands
+func (i *Integration) handleExecuteCommand(ctx context.Context, args []string) (string, error) {
+	args, follow := extractFlag(args, "--follow")
+	req, err := parseExecuteRequest(args)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, err
+	if follow {
+		stream, err := i.client.StreamExecution(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		return i.followEvents(stream)
 	}
 
-	return &config, nil
+	resp, err := i.client.ExecuteProtocol(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf(resp.Error)
+	}
+	return fmt.Sprintf("Protocol execution started: %s", resp.Id), nil
 }
 
-// Helper functions for formatting output
-func formatModel(m *proto.Model) string {
-	return fmt.Sprintf("ID: %s\nName: %s\nType: %s\nParameters: %v\n",
-		m.Id, m.Name, m.Type, m.Parameters)
-}
+// parseExecuteRequest parses the arguments of "execute [--allow-unlinked]
+// [--protocol <protocol_id>] [--priority <priority>] [--tenant <tenant>]
+// <model_id> <context_id> <input> [parameters]"
+func parseExecuteRequest(args []string) (*proto.Protocol, error) {
+	args, req := parseExecuteOptions(args)
+	if len(args) < 3 {
+		return nil, fmt.Errorf("execute command requires model_id, context_id and input")
+	}
 
-func formatModels(models []*proto.Model) string {
-	var result string
-	for _, m := range models {
-		result += formatModel(m) + "\n"
+	req.ModelId = args[0]
+	req.ContextId = args[1]
+	req.Input = args[2]
+	if len(args) > 3 {
+		var err error
+		if req.Params, err = parseParams(args[3]); err != nil {
+			return nil, err
+		}
 	}
-	return result
+	return req, nil
 }
 
-func formatContext(c *proto.Context) string {
-	return fmt.Sprintf("ID: %s\nName: %s\nContent: %s\nMetadata: %v\n",
-		c.Id, c.Name, c.Content, c.Metadata)
+// parseExecuteOptions extracts the options of an execution request from
+// args and returns the remaining arguments
+func parseExecuteOptions(args []string) ([]string, *proto.Protocol) {
+	req := &proto.Protocol{}
+	args, req.AllowUnlinked = extractFlag(args, "--allow-unlinked")
+	args, req.ProtocolId = extractOption(args, "--protocol")
+	args, req.Priority = extractOption(args, "--priority")
+	args, req.Tenant = extractOption(args, "--tenant")
+	return args, req
 }
 
-func formatContexts(contexts []*proto.Context) string {
-	var result string
-	for _, c := range contexts {
-		result += formatContext(c) + "\n"
+// handleWatchCommand follows a running execution, replaying its output so far
+func (i *Integration) handleWatchCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("watch command requires execution_id")
 	}
-	return result
+
+	stream, err := i.client.WatchExecution(ctx, &proto.ProtocolRequest{Id: args[0]})
+	if err != nil {
+		return "", err
+	}
+	return i.followEvents(stream)
 }
 
-func formatData(d *proto.Data) string {
-	return fmt.Sprintf("ID: %s\nType: %s\nContent: %s\nMetadata: %v\n",
-		d.Id, d.Type, string(d.Content), d.Metadata)
+// handleSubscribeCommand prints changes of models, contexts, data and
+// executions as they happen, until the stream ends
+func (i *Integration) handleSubscribeCommand(ctx context.Context, args []string) (string, error) {
+	req := &proto.SubscribeRequest{}
+	rest, documents := extractFlag(args, "--documents")
+	req.IncludeDocuments = documents
+	rest, entities := extractOption(rest, "--entity")
+	rest, operations := extractOption(rest, "--operation")
+	rest, ids := extractOption(rest, "--id")
+	_, req.ResumeToken = extractOption(rest, "--resume")
+	if entities != "" {
+		req.Entities = strings.Split(entities, ",")
+	}
+	if operations != "" {
+		req.Operations = strings.Split(operations, ",")
+	}
+	if ids != "" {
+		req.Ids = strings.Split(ids, ",")
+	}
+
+	stream, err := i.client.Subscribe(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	token := req.ResumeToken
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if ev.Error != "" && ev.Entity == "" {
+			return "", fmt.Errorf(ev.Error)
+		}
+		token = ev.ResumeToken
+		fmt.Fprintln(i.out, formatChangeEvent(ev))
+	}
+
+	if token == "" {
+		return "Subscription ended", nil
+	}
+	return fmt.Sprintf("Subscription ended, resume with --resume %s", token), nil
 }
 
-func formatDataList(data []*proto.Data) string {
-	var result string
-	for _, d := range data {
-		result += formatData(d) + "\n"
+// handleCancelCommand handles execution cancellation commands
+func (i *Integration) handleCancelCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("cancel command requires execution_id")
+	}
+
+	resp, err := i.client.CancelExecution(ctx, &proto.ProtocolRequest{Id: args[0]})
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" && resp.Status == "" {
+		return "", fmt.Errorf(resp.Error)
+	}
+	return fmt.Sprintf("Status: %s", resp.Status), nil
+}
+
+// handleExecutionsCommand handles execution history commands
+func (i *Integration) handleExecutionsCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("executions command requires subcommand")
+	}
+
+	switch args[0] {
+	case "list":
+		req := &proto.ListExecutionsRequest{PageSize: 20}
+		rest := args[1:]
+		rest, req.ModelId = extractOption(rest, "--model")
+		rest, req.ContextId = extractOption(rest, "--context")
+		rest, req.ProtocolId = extractOption(rest, "--protocol")
+		rest, req.Status = extractOption(rest, "--status")
+		rest, req.Tenant = extractOption(rest, "--tenant")
+		rest, req.PageToken = extractOption(rest, "--page")
+		rest, since := extractOption(rest, "--since")
+		_, until := extractOption(rest, "--until")
+
+		var err error
+		if req.CreatedAfter, err = parseTime(since); err != nil {
+			return "", err
+		}
+		if req.CreatedBefore, err = parseTime(until); err != nil {
+			return "", err
+		}
+
+		resp, err := i.client.ListExecutions(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		result := formatExecutions(resp.Executions)
+		if resp.NextPageToken != "" {
+			result += fmt.Sprintf("More results: --page %s\n", resp.NextPageToken)
+		}
+		return result, nil
+
+	case "show":
+		if len(args) < 2 {
+			return "", fmt.Errorf("show execution requires id")
+		}
+		resp, err := i.client.GetExecution(ctx, &proto.ProtocolRequest{Id: args[1]})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatExecution(resp.Execution), nil
+
+	default:
+		return "", fmt.Errorf("unknown executions subcommand: %s", args[0])
+	}
+}
+
+// handleScheduleCommand handles schedule-related commands
+func (i *Integration) handleScheduleCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("schedule command requires subcommand")
+	}
+
+	switch args[0] {
+	case "create":
+		rest, spec := extractOption(args[1:], "--cron")
+		rest, every := extractOption(rest, "--every")
+		if len(rest) < 1 {
+			return "", fmt.Errorf("create schedule requires name")
+		}
+		sch := &proto.Schedule{Name: rest[0], Cron: spec}
+		if every != "" {
+			d, err := time.ParseDuration(every)
+			if err != nil {
+				return "", fmt.Errorf("invalid interval: %s", every)
+			}
+			sch.IntervalSeconds = int64(d / time.Second)
+		}
+		req, err := parseExecuteRequest(rest[1:])
+		if err != nil {
+			return "", err
+		}
+		sch.Request = req
+
+		resp, err := i.client.CreateSchedule(ctx, sch)
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return fmt.Sprintf("Schedule created: %s\n%s", resp.Schedule.Id, formatSchedule(resp.Schedule)), nil
+
+	case "list":
+		resp, err := i.client.ListSchedules(ctx, &proto.ListRequest{
+			PageSize: 10,
+			Filters:  make(map[string]string),
+		})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatSchedules(resp.Schedules), nil
+
+	case "pause", "resume":
+		if len(args) < 2 {
+			return "", fmt.Errorf("%s schedule requires id", args[0])
+		}
+		resp, err := i.client.PauseSchedule(ctx, &proto.PauseScheduleRequest{
+			Id:     args[1],
+			Paused: args[0] == "pause",
+		})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatSchedule(resp.Schedule), nil
+
+	case "delete":
+		if len(args) < 2 {
+			return "", fmt.Errorf("delete schedule requires id")
+		}
+		resp, err := i.client.DeleteSchedule(ctx, &proto.ScheduleRequest{Id: args[1]})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return "Schedule deleted successfully", nil
+
+	default:
+		return "", fmt.Errorf("unknown schedule subcommand: %s", args[0])
+	}
+}
+
+// handleBatchCommand handles batch execution commands
+func (i *Integration) handleBatchCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("batch command requires subcommand")
+	}
+
+	switch args[0] {
+	case "run":
+		rest, inputsFile := extractOption(args[1:], "--inputs")
+		rest, dataType := extractOption(rest, "--data-type")
+		rest, dataFilter := extractOption(rest, "--data-filter")
+		rest, req := parseExecuteOptions(rest)
+		if len(rest) < 2 {
+			return "", fmt.Errorf("run batch requires model_id and context_id")
+		}
+		req.ModelId = rest[0]
+		req.ContextId = rest[1]
+		if len(rest) > 2 {
+			var err error
+			if req.Params, err = parseParams(rest[2]); err != nil {
+				return "", err
+			}
+		}
+
+		batch := &proto.BatchRequest{Request: req, DataType: dataType}
+		if dataFilter != "" {
+			if err := json.Unmarshal([]byte(dataFilter), &batch.DataFilter); err != nil {
+				return "", fmt.Errorf("invalid data filter JSON: %v", err)
+			}
+		}
+		if inputsFile != "" {
+			content, err := os.ReadFile(inputsFile)
+			if err != nil {
+				return "", err
+			}
+			for _, line := range strings.Split(string(content), "\n") {
+				if line = strings.TrimRight(line, "\r"); line != "" {
+					batch.Inputs = append(batch.Inputs, line)
+				}
+			}
+		}
+
+		resp, err := i.client.ExecuteBatch(ctx, batch)
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return fmt.Sprintf("Batch started: %s (%d executions)", resp.Id, resp.Total), nil
+
+	case "status":
+		rest, wait := extractFlag(args[1:], "--wait")
+		if len(rest) < 1 {
+			return "", fmt.Errorf("batch status requires id")
+		}
+		for {
+			resp, err := i.client.GetBatchStatus(ctx, &proto.BatchStatusRequest{Id: rest[0]})
+			if err != nil {
+				return "", err
+			}
+			if resp.Error != "" {
+				return "", fmt.Errorf(resp.Error)
+			}
+			if !wait || finished(resp.Status) {
+				return formatBatchStatus(resp), nil
+			}
+
+			fmt.Fprintf(i.out, "%s %.0f%% (%d/%d)\n", resp.Status, resp.Progress,
+				resp.Completed+resp.Failed+resp.Cancelled, resp.Total)
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(2 * time.Second):
+			}
+		}
+
+	case "export":
+		rest, output := extractOption(args[1:], "--output")
+		if len(rest) < 1 {
+			return "", fmt.Errorf("batch export requires id")
+		}
+		return i.exportBatch(ctx, rest[0], output)
+
+	default:
+		return "", fmt.Errorf("unknown batch subcommand: %s", args[0])
+	}
+}
+
+// batchResult is one line of a batch export
+type batchResult struct {
+	Index            int32  `json:"index"`
+	ExecutionID      string `json:"execution_id"`
+	Input            string `json:"input"`
+	Output           string `json:"output"`
+	Status           string `json:"status"`
+	Error            string `json:"error,omitempty"`
+	DataID           string `json:"data_id,omitempty"`
+	PromptTokens     int32  `json:"prompt_tokens"`
+	CompletionTokens int32  `json:"completion_tokens"`
+	DurationMs       int64  `json:"duration_ms"`
+}
+
+// exportBatch writes the results of a batch as JSON lines to the output
+// file, or to the integration's output when it is empty
+func (i *Integration) exportBatch(ctx context.Context, id, output string) (string, error) {
+	stream, err := i.client.ExportBatch(ctx, &proto.BatchStatusRequest{Id: id})
+	if err != nil {
+		return "", err
+	}
+
+	w := i.out
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	count := 0
+	for {
+		e, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if err := enc.Encode(batchResult{
+			Index:            e.BatchIndex,
+			ExecutionID:      e.Id,
+			Input:            e.Input,
+			Output:           e.Output,
+			Status:           e.Status,
+			Error:            e.Error,
+			DataID:           e.Parameters["data_id"],
+			PromptTokens:     e.PromptTokens,
+			CompletionTokens: e.CompletionTokens,
+			DurationMs:       e.DurationMs,
+		}); err != nil {
+			return "", err
+		}
+		count++
+	}
+
+	if output == "" {
+		return "", nil
+	}
+	return fmt.Sprintf("Exported %d results to %s", count, output), nil
+}
+
+// handleCacheCommand handles response cache commands
+func (i *Integration) handleCacheCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 || args[0] != "purge" {
+		return "", fmt.Errorf("cache command requires subcommand purge")
+	}
+
+	req := &proto.PurgeCacheRequest{}
+	if len(args) > 1 {
+		req.ModelId = args[1]
+	}
+	resp, err := i.client.PurgeCache(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf(resp.Error)
+	}
+	return fmt.Sprintf("Purged %d cached results", resp.Removed), nil
+}
+
+// handleLogLevelCommand shows the server's log level, or changes it
+func (i *Integration) handleLogLevelCommand(ctx context.Context, args []string) (string, error) {
+	req := &proto.LogLevelRequest{}
+	if len(args) > 0 {
+		req.Level = args[0]
+	}
+	resp, err := i.client.SetLogLevel(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf(resp.Error)
+	}
+	if req.Level == "" {
+		return fmt.Sprintf("Log level: %s", resp.Level), nil
+	}
+	return fmt.Sprintf("Log level: %s (was %s)", resp.Level, resp.Previous), nil
+}
+
+// handleQueueCommand shows the executions waiting for and holding workers
+func (i *Integration) handleQueueCommand(ctx context.Context, args []string) (string, error) {
+	_, tenant := extractOption(args, "--tenant")
+
+	resp, err := i.client.GetQueueStats(ctx, &proto.QueueStatsRequest{Tenant: tenant})
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf(resp.Error)
+	}
+	return formatQueueStats(resp), nil
+}
+
+// handleUsageCommand summarizes token usage and cost
+func (i *Integration) handleUsageCommand(ctx context.Context, args []string) (string, error) {
+	req := &proto.UsageReportRequest{}
+	rest, by := extractOption(args, "--by")
+	rest, req.ModelId = extractOption(rest, "--model")
+	rest, req.Tenant = extractOption(rest, "--tenant")
+	rest, req.ApiKey = extractOption(rest, "--api-key")
+	rest, since := extractOption(rest, "--since")
+	_, until := extractOption(rest, "--until")
+	if by != "" {
+		req.GroupBy = strings.Split(by, ",")
+	}
+
+	var err error
+	if req.Start, err = parseTime(since); err != nil {
+		return "", err
+	}
+	if req.End, err = parseTime(until); err != nil {
+		return "", err
+	}
+
+	resp, err := i.client.GetUsageReport(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf(resp.Error)
+	}
+	return formatUsageReport(resp, req.GroupBy), nil
+}
+
+// parseParams parses a JSON object of parameters, whose values may be
+// strings, numbers or booleans
+func parseParams(text string) (*structpb.Struct, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &values); err != nil {
+		return nil, fmt.Errorf("invalid parameters JSON: %v", err)
+	}
+	params, err := structpb.NewStruct(values)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %v", err)
+	}
+	return params, nil
+}
+
+// parseTime parses an RFC 3339 time, a date, or a duration before now
+// such as "24h"
+func parseTime(value string) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return timestamppb.New(time.Now().Add(-d)), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return timestamppb.New(t), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid time %q, use RFC 3339, YYYY-MM-DD or a duration like 24h", value)
+}
+
+// eventReceiver is implemented by the client streams of StreamExecution
+// and WatchExecution
+type eventReceiver interface {
+	Recv() (*proto.ExecutionEvent, error)
+}
+
+// followEvents prints model output and step transitions as they arrive and
+// returns the final status
+func (i *Integration) followEvents(stream eventReceiver) (string, error) {
+	var last *proto.ExecutionEvent
+	for {
+		ev, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch ev.Type {
+		case "delta":
+			fmt.Fprint(i.out, ev.Delta)
+		case "step":
+			fmt.Fprintf(i.out, "\n[%s: %s]\n", ev.StepId, ev.Status)
+		case "status":
+			last = ev
+		}
+	}
+
+	if last == nil {
+		return "", fmt.Errorf("stream ended without a status")
+	}
+	if last.Status == "" && last.Error != "" {
+		return "", fmt.Errorf(last.Error)
+	}
+
+	result := fmt.Sprintf("\nExecution %s: %s", last.ExecutionId, last.Status)
+	if last.Error != "" {
+		result += fmt.Sprintf("\nError: %s", last.Error)
+	}
+	return result, nil
+}
+
+// handleStatusCommand handles protocol status commands
+func (i *Integration) handleStatusCommand(ctx context.Context, args []string) (string, error) {
+	args, wait := extractFlag(args, "--wait")
+	args, every := extractOption(args, "--interval")
+	if len(args) < 1 {
+		return "", fmt.Errorf("status command requires execution_id")
+	}
+
+	interval := 2 * time.Second
+	if every != "" {
+		d, err := time.ParseDuration(every)
+		if err != nil || d <= 0 {
+			return "", fmt.Errorf("invalid interval: %s", every)
+		}
+		interval = d
+	}
+
+	for {
+		resp, err := i.client.GetProtocolStatus(ctx, &proto.ProtocolRequest{Id: args[0]})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" && resp.Status == "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		if !wait || finished(resp.Status) {
+			return formatStatus(resp), nil
+		}
+
+		fmt.Fprintf(i.out, "%s %.0f%%", resp.Status, resp.Progress)
+		if len(resp.CurrentSteps) > 0 {
+			fmt.Fprintf(i.out, " [%s]", strings.Join(resp.CurrentSteps, ", "))
+		}
+		fmt.Fprintln(i.out)
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// finished reports whether an execution status is final
+func finished(status string) bool {
+	switch status {
+	case "completed", "failed", "cancelled":
+		return true
+	}
+	return false
+}
+
+// handleHealthCommand reports the server's health and that of its
+// components, or of the one given, from the standard gRPC health service
+func (i *Integration) handleHealthCommand(ctx context.Context, args []string) (string, error) {
+	services := append([]string{""}, healthcheck.Components...)
+	if len(args) > 0 {
+		services = args
+	}
+
+	var result string
+	healthy := true
+	for _, service := range services {
+		resp, err := i.health.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return "", err
+		}
+		name := service
+		if name == "" {
+			name = "server"
+		}
+		result += fmt.Sprintf("%-10s %s\n", name, resp.Status)
+		healthy = healthy && resp.Status == healthpb.HealthCheckResponse_SERVING
+	}
+	if !healthy {
+		return result, fmt.Errorf("server is not healthy")
+	}
+	return result, nil
+}
+
+// handleTokensCommand handles token counting commands
+func (i *Integration) handleTokensCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) < 3 {
+		return "", fmt.Errorf("tokens command requires model_id, context_id and input")
+	}
+
+	resp, err := i.client.CountTokens(ctx, &proto.TokenCountRequest{
+		ModelId:   args[0],
+		ContextId: args[1],
+		Input:     args[2],
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf(resp.Error)
+	}
+	return formatTokenCount(resp), nil
+}
+
+// handleSecretCommand handles secret-related commands. Values are read
+// from standard input so that they stay out of shell history and process
+// listings.
+func (i *Integration) handleSecretCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("secret command requires subcommand")
+	}
+
+	switch args[0] {
+	case "set":
+		rest, description := extractOption(args[1:], "--description")
+		rest, hosts := extractOption(rest, "--hosts")
+		if len(rest) < 1 {
+			return "", fmt.Errorf("secret set requires name")
+		}
+		value, err := io.ReadAll(i.in)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret value: %v", err)
+		}
+
+		req := &proto.Secret{
+			Name:        rest[0],
+			Value:       strings.TrimRight(string(value), "\r\n"),
+			Description: description,
+		}
+		if hosts != "" {
+			req.Hosts = strings.Split(hosts, ",")
+		}
+		resp, err := i.client.PutSecret(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return fmt.Sprintf("Secret %s stored", resp.Secret.Name), nil
+
+	case "list":
+		resp, err := i.client.ListSecrets(ctx, &proto.ListRequest{})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatSecrets(resp.Secrets), nil
+
+	case "delete":
+		if len(args) < 2 {
+			return "", fmt.Errorf("secret delete requires name")
+		}
+		resp, err := i.client.DeleteSecret(ctx, &proto.SecretRequest{Name: args[1]})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return fmt.Sprintf("Secret %s deleted", args[1]), nil
+
+	default:
+		return "", fmt.Errorf("unknown secret subcommand: %s", args[0])
+	}
+}
+
+// handleDataCommand handles data-related commands
+func (i *Integration) handleDataCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("data command requires subcommand")
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 3 {
+			return "", fmt.Errorf("add data requires type and content")
+		}
+
+		metadata := make(map[string]string)
+		if len(args) > 3 {
+			err := json.Unmarshal([]byte(args[3]), &metadata)
+			if err != nil {
+				return "", fmt.Errorf("invalid metadata JSON: %v", err)
+			}
+		}
+
+		resp, err := i.client.AddData(ctx, &proto.Data{
+			Type:     args[1],
+			Content:  []byte(args[2]),
+			Metadata: metadata,
+		})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return fmt.Sprintf("Data added: %s", resp.Data.Id), nil
+
+	case "get":
+		if len(args) < 2 {
+			return "", fmt.Errorf("get data requires id")
+		}
+		resp, err := i.client.GetData(ctx, &proto.DataRequest{Id: args[1]})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatData(resp.Data), nil
+
+	case "list":
+		resp, err := i.client.ListData(ctx, &proto.ListRequest{
+			PageSize: 10,
+			Filters:  make(map[string]string),
+		})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return formatDataList(resp.Data), nil
+
+	case "delete":
+		if len(args) < 2 {
+			return "", fmt.Errorf("delete data requires id")
+		}
+		resp, err := i.client.DeleteData(ctx, &proto.DataRequest{Id: args[1]})
+		if err != nil {
+			return "", err
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf(resp.Error)
+		}
+		return "Data deleted successfully", nil
+
+	default:
+		return "", fmt.Errorf("unknown data subcommand: %s", args[0])
+	}
+}
+
+// loadConfig loads the Cursor MCP configuration
+func loadConfig() (*Config, error) {
+	configPath := filepath.Join("configs", "cursor.json")
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// extractFlag removes a boolean flag from args and reports whether it was present
+func extractFlag(args []string, flag string) ([]string, bool) {
+	var rest []string
+	found := false
+	for _, arg := range args {
+		if arg == flag {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
+}
+
+// extractOption removes a "--name value" option from args and returns its value
+func extractOption(args []string, name string) ([]string, string) {
+	var rest []string
+	var value string
+	for j := 0; j < len(args); j++ {
+		if args[j] == name && j+1 < len(args) {
+			value = args[j+1]
+			j++
+			continue
+		}
+		rest = append(rest, args[j])
+	}
+	return rest, value
+}
+
+// Helper functions for formatting output
+func formatModel(m *proto.Model) string {
+	return fmt.Sprintf("ID: %s\nName: %s\nType: %s\nVersion: %d\nParameters: %v\n",
+		m.Id, m.Name, m.Type, m.Version, m.Parameters)
+}
+
+func formatModels(models []*proto.Model) string {
+	var result string
+	for _, m := range models {
+		result += formatModel(m) + "\n"
+	}
+	return result
+}
+
+func formatModelAliases(aliases []*proto.ModelAlias) string {
+	var result string
+	for _, a := range aliases {
+		result += fmt.Sprintf("%s -> version %d (updated %s)\n",
+			a.Ref, a.Version, a.UpdatedAt.AsTime().Local().Format(time.RFC3339))
+	}
+	return result
+}
+
+func formatModelHealth(models []*proto.ModelHealth) string {
+	if len(models) == 0 {
+		return "No models probed yet\n"
+	}
+	var result string
+	for _, h := range models {
+		result += fmt.Sprintf("%s  %-9s  availability %.0f%%  avg latency %s",
+			h.ModelId, h.Status, h.Availability*100, time.Duration(h.AverageLatencyMs)*time.Millisecond)
+		if h.LastCheck != nil {
+			result += fmt.Sprintf("  last checked %s", h.LastCheck.CheckedAt.AsTime().Local().Format(time.RFC3339))
+			if h.LastCheck.Error != "" {
+				result += fmt.Sprintf(" - %s", h.LastCheck.Error)
+			}
+		}
+		result += "\n"
+	}
+	return result
+}
+
+func formatModelSchema(s *proto.ModelSchema) string {
+	names := make([]string, 0, len(s.Parameters))
+	for name := range s.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := fmt.Sprintf("Parameters of %s models:\n", s.Type)
+	for _, name := range names {
+		p := s.Parameters[name]
+		result += fmt.Sprintf("  %-24s %-8s", name, p.Type)
+		if p.Min != nil {
+			result += fmt.Sprintf("  min %g", *p.Min)
+		}
+		if p.Max != nil {
+			result += fmt.Sprintf("  max %g", *p.Max)
+		}
+		if len(p.Enum) > 0 {
+			result += fmt.Sprintf("  one of %s", strings.Join(p.Enum, ", "))
+		}
+		if p.Default != "" {
+			result += fmt.Sprintf("  default %s", p.Default)
+		}
+		if p.Required {
+			result += "  required"
+		}
+		if p.ModelOnly {
+			result += "  model only"
+		}
+		if p.Description != "" {
+			result += fmt.Sprintf("  - %s", p.Description)
+		}
+		result += "\n"
+	}
+	return result
+}
+
+func formatContext(c *proto.Context) string {
+	return fmt.Sprintf("ID: %s\nName: %s\nContent: %s\nModels: %v\nMetadata: %v\n",
+		c.Id, c.Name, c.Content, c.ModelIds, c.Metadata)
+}
+
+func formatContexts(contexts []*proto.Context) string {
+	var result string
+	for _, c := range contexts {
+		result += formatContext(c) + "\n"
+	}
+	return result
+}
+
+func formatProtocol(p *proto.ProtocolDefinition) string {
+	return fmt.Sprintf("ID: %s\nName: %s\nType: %s\nDescription: %s\nSteps: %v\nParameters: %v\n",
+		p.Id, p.Name, p.Type, p.Description, p.Steps, p.Parameters)
+}
+
+func formatProtocols(protocols []*proto.ProtocolDefinition) string {
+	var result string
+	for _, p := range protocols {
+		result += formatProtocol(p) + "\n"
+	}
+	return result
+}
+
+func formatStatus(s *proto.ProtocolStatus) string {
+	result := fmt.Sprintf("Status: %s\nProgress: %.0f%%", s.Status, s.Progress)
+	if len(s.CurrentSteps) > 0 {
+		result += fmt.Sprintf("\nCurrent steps: %s", strings.Join(s.CurrentSteps, ", "))
+	}
+	if s.StartedAt != nil {
+		result += fmt.Sprintf("\nStarted: %s", s.StartedAt.AsTime().Local().Format(time.RFC3339))
+	}
+	if s.FinishedAt != nil {
+		result += fmt.Sprintf("\nFinished: %s", s.FinishedAt.AsTime().Local().Format(time.RFC3339))
+	}
+	result += fmt.Sprintf("\nDuration: %s", time.Duration(s.DurationMs)*time.Millisecond)
+	result += fmt.Sprintf("\nAttempts: %d", s.Attempts)
+	result += fmt.Sprintf("\nTokens: %d prompt, %d completion", s.PromptTokens, s.CompletionTokens)
+	result += fmt.Sprintf("\nCost: %.4f", s.Cost)
+	if s.Cached {
+		result += "\nCached: yes"
+	}
+	if s.Error != "" {
+		result += fmt.Sprintf("\nError: %s", s.Error)
+	}
+	for _, step := range s.Steps {
+		result += fmt.Sprintf("\n  %s (%s): %s", step.Id, step.Type, step.Status)
+		if step.ModelId != "" {
+			result += fmt.Sprintf(" via %s@v%d", step.ModelId, step.ModelVersion)
+		}
+		if step.Error != "" {
+			result += fmt.Sprintf(" - %s", step.Error)
+		}
+	}
+	if s.Output != "" {
+		result += fmt.Sprintf("\nOutput: %s", s.Output)
+	}
+	return result
+}
+
+func formatExecution(e *proto.Execution) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ID: %s\nStatus: %s\n", e.Id, e.Status)
+	fmt.Fprintf(&b, "Priority: %s\nTenant: %s\n", e.Priority, e.Tenant)
+	if e.ProtocolId != "" {
+		fmt.Fprintf(&b, "Protocol: %s\n", e.ProtocolId)
+	}
+	fmt.Fprintf(&b, "Model: %s (version %d)\nContext: %s\n", e.ModelId, e.ModelVersion, e.ContextId)
+	if e.ModelRef != "" {
+		fmt.Fprintf(&b, "Model reference: %s\n", e.ModelRef)
+	}
+	if e.ServedBy != "" && (e.ServedBy != e.ModelId || e.ServedByVersion != e.ModelVersion) {
+		fmt.Fprintf(&b, "Served by: %s (version %d)\n", e.ServedBy, e.ServedByVersion)
+	}
+	for _, pin := range e.Models {
+		fmt.Fprintf(&b, "Pinned: %s\n", pin)
+	}
+	if e.CreatedAt != nil {
+		fmt.Fprintf(&b, "Created: %s\n", e.CreatedAt.AsTime().Local().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, "Duration: %s\n", time.Duration(e.DurationMs)*time.Millisecond)
+	fmt.Fprintf(&b, "Tokens: %d prompt, %d completion\n", e.PromptTokens, e.CompletionTokens)
+	fmt.Fprintf(&b, "Cost: %.4f\n", e.Cost)
+	if e.ApiKey != "" {
+		fmt.Fprintf(&b, "API key: %s\n", e.ApiKey)
+	}
+	fmt.Fprintf(&b, "Attempts: %d\n", e.Attempts)
+	if e.Cached {
+		fmt.Fprintf(&b, "Cached: yes\n")
+	}
+	fmt.Fprintf(&b, "Parameters: %v\n", e.Parameters)
+	fmt.Fprintf(&b, "Input: %s\n", e.Input)
+	fmt.Fprintf(&b, "Output: %s\n", e.Output)
+	if e.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", e.Error)
+	}
+	return b.String()
+}
+
+func formatExecutions(executions []*proto.Execution) string {
+	var result string
+	for _, e := range executions {
+		created := ""
+		if e.CreatedAt != nil {
+			created = e.CreatedAt.AsTime().Local().Format(time.RFC3339)
+		}
+		result += fmt.Sprintf("%s  %-9s  %s  model=%s context=%s  %s  %d tokens\n",
+			e.Id, e.Status, created, e.ModelId, e.ContextId,
+			time.Duration(e.DurationMs)*time.Millisecond, e.PromptTokens+e.CompletionTokens)
+	}
+	return result
+}
+
+func formatSchedule(s *proto.Schedule) string {
+	trigger := s.Cron
+	if trigger == "" {
+		trigger = fmt.Sprintf("every %s", time.Duration(s.IntervalSeconds)*time.Second)
+	}
+	result := fmt.Sprintf("ID: %s\nName: %s\nTrigger: %s\nPaused: %t", s.Id, s.Name, trigger, s.Paused)
+	if s.Request != nil {
+		result += fmt.Sprintf("\nModel: %s\nContext: %s", s.Request.ModelId, s.Request.ContextId)
+		if s.Request.ProtocolId != "" {
+			result += fmt.Sprintf("\nProtocol: %s", s.Request.ProtocolId)
+		}
+	}
+	if s.LastRunAt != nil {
+		result += fmt.Sprintf("\nLast run: %s (%s)", s.LastRunAt.AsTime().Local().Format(time.RFC3339), s.LastExecutionId)
+	}
+	if s.NextRunAt != nil && !s.Paused {
+		result += fmt.Sprintf("\nNext run: %s", s.NextRunAt.AsTime().Local().Format(time.RFC3339))
+	}
+	if s.LastError != "" {
+		result += fmt.Sprintf("\nLast error: %s", s.LastError)
+	}
+	return result
+}
+
+func formatSchedules(schedules []*proto.Schedule) string {
+	var result string
+	for _, s := range schedules {
+		result += formatSchedule(s) + "\n\n"
+	}
+	return result
+}
+
+func formatBatchStatus(s *proto.BatchStatus) string {
+	return fmt.Sprintf("ID: %s\nStatus: %s\nProgress: %.0f%%\nTotal: %d\nPending: %d\nRunning: %d\nCompleted: %d\nFailed: %d\nCancelled: %d",
+		s.Id, s.Status, s.Progress, s.Total, s.Pending, s.Running, s.Completed, s.Failed, s.Cancelled)
+}
+
+func formatQueueStats(s *proto.QueueStats) string {
+	result := "Priority totals:\n"
+	for _, q := range s.Priorities {
+		result += fmt.Sprintf("  %-11s  %d pending, %d running\n", q.Priority, q.Pending, q.Running)
+	}
+	if len(s.Queues) > 0 {
+		result += "Queues:\n"
+	}
+	for _, q := range s.Queues {
+		result += fmt.Sprintf("  %-11s  %-16s  %d pending, %d running\n", q.Priority, q.Tenant, q.Pending, q.Running)
+	}
+	return result
+}
+
+func formatUsageReport(r *proto.UsageReport, groupBy []string) string {
+	var result string
+	if len(groupBy) > 0 {
+		for _, row := range r.Rows {
+			values := map[string]string{"model": row.ModelId, "tenant": row.Tenant, "api_key": row.ApiKey, "day": row.Day}
+			var group []string
+			for _, dim := range groupBy {
+				value := values[dim]
+				if value == "" {
+					value = "-"
+				}
+				group = append(group, dim+"="+value)
+			}
+			result += fmt.Sprintf("%s  %s\n", strings.Join(group, " "), formatUsage(row))
+		}
+	}
+	if r.Total != nil {
+		result += fmt.Sprintf("Total  %s\n", formatUsage(r.Total))
+	}
+	return result
+}
+
+func formatUsage(u *proto.UsageRow) string {
+	return fmt.Sprintf("%d executions, %d calls, %d prompt + %d completion tokens, cost %.4f",
+		u.Executions, u.Calls, u.PromptTokens, u.CompletionTokens, u.Cost)
+}
+
+func formatTokenCount(t *proto.TokenCountResponse) string {
+	window, budget := "unknown", "unknown"
+	if t.ContextWindow > 0 {
+		window = fmt.Sprintf("%d", t.ContextWindow)
+		budget = fmt.Sprintf("%d", t.PromptBudget)
+	}
+	return fmt.Sprintf("Tokenizer: %s\nContext tokens: %d\nInput tokens: %d\nTotal tokens: %d\nContext window: %s\nPrompt budget: %s\n",
+		t.Tokenizer, t.ContextTokens, t.InputTokens, t.TotalTokens, window, budget)
+}
+
+func formatData(d *proto.Data) string {
+	return fmt.Sprintf("ID: %s\nType: %s\nContent: %s\nMetadata: %v\n",
+		d.Id, d.Type, string(d.Content), d.Metadata)
+}
+
+func formatDataList(data []*proto.Data) string {
+	var result string
+	for _, d := range data {
+		result += formatData(d) + "\n"
+	}
+	return result
+}
+
+func formatChangeEvent(ev *proto.ChangeEvent) string {
+	line := fmt.Sprintf("%s %-9s %-7s %s", ev.Time.AsTime().Local().Format(time.RFC3339), ev.Entity, ev.Operation, ev.Id)
+	if ev.Error != "" {
+		line += fmt.Sprintf(" (error: %s)", ev.Error)
+	}
+	switch doc := ev.Document.(type) {
+	case *proto.ChangeEvent_Model:
+		line += "\n" + formatModel(doc.Model)
+	case *proto.ChangeEvent_Context:
+		line += "\n" + formatContext(doc.Context)
+	case *proto.ChangeEvent_Data:
+		line += "\n" + formatData(doc.Data)
+	case *proto.ChangeEvent_Execution:
+		line += "\n" + formatExecution(doc.Execution)
+	}
+	return line
+}
+
+func formatSecrets(secrets []*proto.Secret) string {
+	if len(secrets) == 0 {
+		return "No secrets\n"
+	}
+	var result string
+	for _, s := range secrets {
+		result += fmt.Sprintf("%s  key %s  updated %s", s.Name, s.KeyId, s.UpdatedAt.AsTime().Local().Format(time.RFC3339))
+		if len(s.Hosts) > 0 {
+			result += fmt.Sprintf("  hosts %s", strings.Join(s.Hosts, ","))
+		}
+		if s.Description != "" {
+			result += fmt.Sprintf("  - %s", s.Description)
+		}
+		result += "\n"
 	}
 	return result
 }
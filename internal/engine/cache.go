@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/cache"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+)
+
+// Cache policies, set by the "cache" model parameter. Models without one
+// are not cached.
+const (
+	CacheAlways        = "always"        // cache every execution
+	CacheDeterministic = "deterministic" // cache executions run at temperature 0
+)
+
+// cacheMaterial is what identifies an execution's result
+type cacheMaterial struct {
+	Models          []cachedModel
+	ProtocolID      string
+	ProtocolUpdated time.Time
+	Type            string
+	Context         string
+	Input           string
+	Parameters      map[string]string
+}
+
+// cachedModel identifies a version of a model the pipeline can call
+type cachedModel struct {
+	ID      string
+	Version int
+	Updated time.Time
+}
+
+// cacheKey returns the key an execution's result is cached under and how
+// long it is kept, or an empty key when the execution must not be cached:
+// caching is off, its model has not opted in, or one of its steps stores
+// data and so must run every time. The key covers every model the
+// pipeline can call, so editing a step's model or a router's routes makes
+// earlier results miss.
+func (e *Engine) cacheKey(ctx context.Context, execution *protocol.Execution, m *model.Model, def *protocol.Protocol, steps []protocol.Step) (string, time.Duration) {
+	if e.cfg.Cache == nil || m == nil {
+		return "", 0
+	}
+
+	params := mergeParams(m, execution.Metadata)
+	switch m.Parameters["cache"] {
+	case CacheAlways:
+	case CacheDeterministic:
+		if t, err := strconv.ParseFloat(params["temperature"], 64); err != nil || t != 0 {
+			return "", 0
+		}
+	default:
+		return "", 0
+	}
+	for _, step := range steps {
+		if step.Type == protocol.StepStore {
+			return "", 0
+		}
+	}
+
+	models, err := e.callableModels(ctx, execution, m, steps)
+	if err != nil {
+		// The pipeline reports the model it cannot find
+		return "", 0
+	}
+	material := cacheMaterial{
+		Models:     models,
+		Type:       execution.Type,
+		Context:    execution.Context,
+		Input:      execution.Input,
+		Parameters: execution.Metadata,
+	}
+	if def != nil {
+		material.ProtocolID = def.ID.Hex()
+		material.ProtocolUpdated = def.UpdatedAt
+	}
+	key, err := cache.Key(material)
+	if err != nil {
+		return "", 0
+	}
+
+	ttl := e.cfg.CacheTTL
+	if seconds, err := strconv.Atoi(m.Parameters["cache_ttl_seconds"]); err == nil && seconds > 0 {
+		ttl = time.Duration(seconds) * time.Second
+	}
+	return key, ttl
+}
+
+// callableModels returns the models the pipeline can call: the
+// execution's model, those set by the steps' "model_id", and the models
+// routers among them route to
+func (e *Engine) callableModels(ctx context.Context, execution *protocol.Execution, m *model.Model, steps []protocol.Step) ([]cachedModel, error) {
+	var models []cachedModel
+	seen := make(map[cachedModel]bool)
+	err := e.eachModel(ctx, execution, m, steps, func(ref string, m *model.Model) {
+		c := cachedModel{ID: m.ID.Hex(), Version: m.Version, Updated: m.UpdatedAt}
+		if !seen[c] {
+			seen[c] = true
+			models = append(models, c)
+		}
+	})
+	return models, err
+}
+
+// cached returns the cached result stored under key
+func (e *Engine) cached(ctx context.Context, key string) (string, bool) {
+	entry, ok, err := e.cfg.Cache.Get(ctx, key)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error reading cache", "error", err)
+		return "", false
+	}
+	if !ok {
+		return "", false
+	}
+	return entry.Result, true
+}
+
+// storeCached caches an execution's result under key
+func (e *Engine) storeCached(ctx context.Context, key string, ttl time.Duration, execution *protocol.Execution, result string) {
+	entry := &cache.Entry{Key: key, ModelID: execution.ModelID, Result: result}
+	if err := e.cfg.Cache.Put(ctx, entry, ttl); err != nil {
+		slog.ErrorContext(ctx, "Error caching result", "execution_id", execution.ID.Hex(), "error", err)
+	}
+}
@@ -0,0 +1,642 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/cache"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/metrics"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/provider"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/data"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/DavutcanJ/mongo-mcp-server/internal/engine")
+
+// errCancelled is the cause of executions stopped by Cancel
+var errCancelled = errors.New("execution cancelled")
+
+// errDraining is the cause of executions stopped by Drain, returned to the
+// queue
+var errDraining = errors.New("engine draining")
+
+// Config controls how executions run
+type Config struct {
+	Timeout        time.Duration             // default deadline of an execution
+	RetryAttempts  int                       // retries of a failed provider call
+	RetryBackoff   time.Duration             // delay before the first retry, doubled on each one
+	Workers        int                       // executions run at once by this process
+	WorkerID       string                    // identifies this process in execution leases
+	Lease          time.Duration             // how long a claimed execution stays leased without a heartbeat
+	PollInterval   time.Duration             // how often the queue is checked for work
+	MaxClaims      int                       // claims after which an execution whose workers keep dying is failed
+	Weights        map[string]int            // shares of the workers per priority, DefaultWeights if empty
+	ProviderLimits map[string]int            // concurrent calls allowed per provider, unbounded if absent
+	Cache          *cache.Cache              // results of executions whose model opts in, nil to disable
+	CacheTTL       time.Duration             // how long results are cached unless the model sets cache_ttl_seconds
+	Healthy        func(modelID string) bool // whether routers may route to a model, all are if nil
+	Metrics        *metrics.Metrics          // records provider calls, nil to disable
+}
+
+// Engine runs protocol executions in the background, step by step.
+// Executions are queued in MongoDB: every engine sharing the database
+// claims pending executions under a lease that it renews while they run,
+// and executions whose lease expires are claimed again by another engine.
+type Engine struct {
+	cfg       Config
+	models    *model.ModelRepository
+	protocols *protocol.ProtocolRepository
+	data      *data.DataRepository
+	providers *provider.Registry
+	events    *hub
+	sched     *scheduler
+	limits    *limiter
+	rotation  *rotation
+	slots     chan struct{}
+	wake      chan struct{}
+	polled    atomic.Int64 // unix nanoseconds of the last queue poll
+	stopping  chan struct{}
+	stopOnce  sync.Once
+	claiming  sync.WaitGroup // the claim loop
+	executing sync.WaitGroup // executions started by the claim loop
+
+	mu        sync.Mutex
+	running   map[string]context.CancelCauseFunc
+	submitted []submission
+}
+
+// submission is an interactive execution submitted to this engine
+type submission struct {
+	id    string
+	class protocol.QueueClass
+}
+
+// New creates a new Engine
+func New(cfg Config, models *model.ModelRepository, protocols *protocol.ProtocolRepository, data *data.DataRepository, providers *provider.Registry) *Engine {
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 500 * time.Millisecond
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 10
+	}
+	if cfg.WorkerID == "" {
+		cfg.WorkerID = workerID()
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 30 * time.Second
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.MaxClaims <= 0 {
+		cfg.MaxClaims = 3
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = 24 * time.Hour
+	}
+
+	return &Engine{
+		cfg:       cfg,
+		models:    models,
+		protocols: protocols,
+		data:      data,
+		providers: providers,
+		events:    newHub(),
+		sched:     newScheduler(cfg.Weights),
+		limits:    newLimiter(cfg.ProviderLimits),
+		rotation:  newRotation(),
+		slots:     make(chan struct{}, cfg.Workers),
+		wake:      make(chan struct{}, 1),
+		stopping:  make(chan struct{}),
+		running:   make(map[string]context.CancelCauseFunc),
+	}
+}
+
+// workerID names this process uniquely among the engines sharing a database
+func workerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), primitive.NewObjectID().Hex())
+}
+
+// WorkerID returns the name this engine holds execution leases under
+func (e *Engine) WorkerID() string {
+	return e.cfg.WorkerID
+}
+
+// Start claims and runs queued executions until ctx is done or the engine
+// is drained
+func (e *Engine) Start(ctx context.Context) {
+	e.claiming.Add(1)
+	go func() {
+		defer e.claiming.Done()
+		ticker := time.NewTicker(e.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			e.claim(ctx)
+			e.polled.Store(time.Now().UnixNano())
+			select {
+			case <-ctx.Done():
+				return
+			case <-e.stopping:
+				return
+			case <-ticker.C:
+			case <-e.wake:
+			}
+		}
+	}()
+}
+
+// Drain stops claiming executions and waits for the running ones to
+// finish. When ctx is done first, the executions still running are
+// stopped and returned to the queue, to be run again from their first
+// step by another engine, and ctx's error is returned.
+func (e *Engine) Drain(ctx context.Context) error {
+	e.stopOnce.Do(func() { close(e.stopping) })
+	e.claiming.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		e.executing.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	e.mu.Lock()
+	slog.Warn("Returning running executions to the queue", "executions", len(e.running))
+	for _, cancel := range e.running {
+		cancel(errDraining)
+	}
+	e.mu.Unlock()
+	<-done
+	return ctx.Err()
+}
+
+// Check reports an error when the engine is not taking work from the
+// queue: it was not started, its last poll is long overdue, or it is
+// draining
+func (e *Engine) Check() error {
+	select {
+	case <-e.stopping:
+		return errors.New("engine draining")
+	default:
+	}
+	polled := e.polled.Load()
+	if polled == 0 {
+		return errors.New("engine not started")
+	}
+	if since := time.Since(time.Unix(0, polled)); since > 3*e.cfg.PollInterval+e.cfg.Lease {
+		return fmt.Errorf("queue last polled %s ago", since.Round(time.Second))
+	}
+	return nil
+}
+
+// Workers reports how many of the engine's workers are running executions
+func (e *Engine) Workers() (busy, total int) {
+	return len(e.slots), cap(e.slots)
+}
+
+// Submit queues a pending execution. When the fair schedule picks the
+// class of an interactive execution, this engine claims it ahead of the
+// class's other executions, so that its events can be watched here.
+func (e *Engine) Submit(execution *protocol.Execution) {
+	if execution.Priority == protocol.PriorityInteractive {
+		id := execution.ID.Hex()
+		e.events.open(id)
+
+		e.mu.Lock()
+		e.submitted = append(e.submitted, submission{
+			id:    id,
+			class: protocol.QueueClass{Priority: execution.Priority, Tenant: execution.Tenant},
+		})
+		e.mu.Unlock()
+	}
+
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// claim starts queued executions while there are free workers
+func (e *Engine) claim(ctx context.Context) {
+	var pending map[protocol.QueueClass]int64
+	for {
+		select {
+		case <-e.stopping:
+			return
+		default:
+		}
+		select {
+		case e.slots <- struct{}{}:
+		default:
+			return
+		}
+
+		execution, err := e.claimNext(ctx, &pending)
+		if err != nil || execution == nil {
+			<-e.slots
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "Error claiming execution", "error", err)
+			}
+			return
+		}
+		e.start(execution)
+	}
+}
+
+// claimNext follows the fair schedule between the classes with pending
+// work, then claims executions whose lease expired. pending holds the
+// number of pending executions per class, loaded on first use.
+func (e *Engine) claimNext(ctx context.Context, pending *map[protocol.QueueClass]int64) (*protocol.Execution, error) {
+	if *pending == nil {
+		depths, err := e.protocols.QueueDepths(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		*pending = make(map[protocol.QueueClass]int64, len(depths))
+		for _, d := range depths {
+			(*pending)[d.QueueClass] = d.Pending
+		}
+	}
+
+	for {
+		class, ok := e.sched.next(*pending)
+		if !ok {
+			break
+		}
+		execution, err := e.claimFrom(ctx, class)
+		if err != nil {
+			return nil, err
+		}
+		if execution != nil {
+			(*pending)[class]--
+			e.sched.claimed(class)
+			return execution, nil
+		}
+		// Taken by other engines since the depths were counted
+		(*pending)[class] = 0
+	}
+
+	execution, err := e.protocols.ClaimExecution(ctx, protocol.ClaimFilter{}, e.cfg.WorkerID, e.cfg.Lease)
+	if err != nil || execution == nil {
+		return nil, err
+	}
+	e.sched.claimed(protocol.QueueClass{Priority: execution.Priority, Tenant: execution.Tenant})
+	return execution, nil
+}
+
+// claimFrom claims a pending execution of class, those submitted to this
+// engine first
+func (e *Engine) claimFrom(ctx context.Context, class protocol.QueueClass) (*protocol.Execution, error) {
+	for {
+		id, ok := e.popSubmitted(class)
+		if !ok {
+			break
+		}
+		execution, err := e.protocols.ClaimExecution(ctx, protocol.ClaimFilter{ExecutionID: id}, e.cfg.WorkerID, e.cfg.Lease)
+		if err != nil {
+			return nil, err
+		}
+		if execution != nil {
+			return execution, nil
+		}
+		// Claimed by another engine, or cancelled while queued
+		e.events.close(id)
+	}
+
+	return e.protocols.ClaimExecution(ctx, protocol.ClaimFilter{QueueClass: class}, e.cfg.WorkerID, e.cfg.Lease)
+}
+
+// popSubmitted removes the oldest execution of class submitted to this
+// engine
+func (e *Engine) popSubmitted(class protocol.QueueClass) (string, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i, s := range e.submitted {
+		if s.class == class {
+			e.submitted = append(e.submitted[:i], e.submitted[i+1:]...)
+			return s.id, true
+		}
+	}
+	return "", false
+}
+
+// start runs a claimed execution in the background. The execution is
+// stopped at its deadline: the "timeout_seconds" parameter, or else the
+// engine's default timeout, counted from its first claim.
+func (e *Engine) start(execution *protocol.Execution) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	if execution.Deadline.IsZero() {
+		timeout := e.cfg.Timeout
+		if seconds, err := strconv.Atoi(execution.Metadata["timeout_seconds"]); err == nil && seconds > 0 {
+			timeout = time.Duration(seconds) * time.Second
+		}
+		if timeout > 0 {
+			execution.Deadline = time.Now().Add(timeout)
+		}
+	}
+	stop := func() {}
+	if !execution.Deadline.IsZero() {
+		ctx, stop = context.WithDeadline(ctx, execution.Deadline)
+	}
+
+	id := execution.ID.Hex()
+	e.mu.Lock()
+	e.running[id] = cancel
+	e.mu.Unlock()
+
+	e.events.open(id)
+	e.executing.Add(1)
+	go func() {
+		defer e.executing.Done()
+		defer func() {
+			e.mu.Lock()
+			delete(e.running, id)
+			e.mu.Unlock()
+			stop()
+			cancel(nil)
+			<-e.slots
+			select {
+			case e.wake <- struct{}{}:
+			default:
+			}
+		}()
+
+		done := make(chan struct{})
+		defer close(done)
+		go e.heartbeat(execution, cancel, done)
+		e.run(ctx, execution)
+	}()
+}
+
+// heartbeat renews an execution's lease until done is closed. If the lease
+// is lost the execution is stopped, as cancelled when it was cancelled
+// through another engine.
+func (e *Engine) heartbeat(execution *protocol.Execution, cancel context.CancelCauseFunc, done <-chan struct{}) {
+	ticker := time.NewTicker(e.cfg.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, stop := context.WithTimeout(context.Background(), e.cfg.Lease/3)
+		err := e.protocols.RenewLease(ctx, execution, e.cfg.Lease)
+		if err == protocol.ErrLeaseLost {
+			if stored, _ := e.protocols.GetExecutionStatus(ctx, execution.ID.Hex()); stored != nil && stored.Status == protocol.StatusCancelled {
+				err = errCancelled
+			}
+			stop()
+			cancel(err)
+			return
+		}
+		stop()
+		if err != nil {
+			slog.Error("Error renewing lease", "execution_id", execution.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// Cancel stops an execution running in this process, including its
+// in-flight provider requests. It reports whether the execution was found.
+func (e *Engine) Cancel(executionID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	cancel, ok := e.running[executionID]
+	if ok {
+		cancel(errCancelled)
+	}
+	return ok
+}
+
+// Watch returns the events an execution has emitted so far and a channel
+// of the events that follow, closed when the execution finishes. ok is
+// false when the execution's events are not buffered in this process.
+func (e *Engine) Watch(executionID string) (replay []Event, events <-chan Event, cancel func(), ok bool) {
+	return e.events.subscribe(executionID)
+}
+
+// run executes the pipeline of an execution and records the outcome. It is
+// traced as part of the trace of the request that created the execution.
+func (e *Engine) run(ctx context.Context, execution *protocol.Execution) {
+	defer e.events.close(execution.ID.Hex())
+
+	ctx, span := tracer.Start(tracing.Extract(ctx, execution.TraceContext), "execution",
+		trace.WithAttributes(
+			attribute.String("mcp.execution.id", execution.ID.Hex()),
+			attribute.String("mcp.model.id", execution.ModelID),
+			attribute.String("mcp.protocol.id", execution.ProtocolID),
+			attribute.Int("mcp.execution.claim", execution.Claims),
+		))
+	defer func() {
+		span.SetAttributes(attribute.String("mcp.execution.status", execution.Status))
+		if execution.Error != "" {
+			span.SetStatus(codes.Error, execution.Error)
+		}
+		span.End()
+	}()
+
+	if execution.Claims > 1 {
+		// A previous worker died mid-run: start over
+		slog.WarnContext(ctx, "Execution reclaimed", "execution_id", execution.ID.Hex(), "claim", execution.Claims)
+		execution.Restart()
+	}
+	execution.StartedAt = time.Now()
+	e.save(execution)
+	e.publishStatus(execution)
+
+	var result string
+	var err error
+	if execution.Claims > e.cfg.MaxClaims {
+		err = fmt.Errorf("execution abandoned after %d claims", execution.Claims-1)
+	} else {
+		result, err = e.runPipeline(ctx, execution)
+	}
+	switch {
+	case errors.Is(context.Cause(ctx), protocol.ErrLeaseLost):
+		slog.WarnContext(ctx, "Execution stopped", "execution_id", execution.ID.Hex(), "error", protocol.ErrLeaseLost)
+		return
+	case err == nil:
+		execution.Status = protocol.StatusCompleted
+		execution.Result = result
+	case errors.Is(context.Cause(ctx), errDraining):
+		e.release(execution)
+		return
+	case errors.Is(context.Cause(ctx), errCancelled):
+		execution.Status = protocol.StatusCancelled
+		execution.Error = errCancelled.Error()
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		execution.Status = protocol.StatusFailed
+		execution.Error = fmt.Sprintf("execution timed out at %s: %v", execution.Deadline.Format(time.RFC3339), err)
+	default:
+		execution.Status = protocol.StatusFailed
+		execution.Error = err.Error()
+	}
+	execution.FinishedAt = time.Now()
+	e.save(execution)
+	e.publishStatus(execution)
+}
+
+// release returns an execution stopped by Drain to the queue
+func (e *Engine) release(execution *protocol.Execution) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := e.protocols.ReleaseExecution(ctx, execution); err != nil {
+		slog.Error("Error returning execution to the queue", "execution_id", execution.ID.Hex(), "error", err)
+		return
+	}
+	slog.Info("Execution returned to the queue", "execution_id", execution.ID.Hex())
+	execution.Status = protocol.StatusPending
+	e.publishStatus(execution)
+}
+
+// publishStatus emits the execution's current status to its watchers
+func (e *Engine) publishStatus(execution *protocol.Execution) {
+	e.events.publish(Event{
+		ExecutionID: execution.ID.Hex(),
+		Type:        EventStatus,
+		Status:      execution.Status,
+		Output:      execution.Result,
+		Error:       execution.Error,
+	})
+}
+
+// runPipeline loads the execution's steps and runs them in dependency
+// order, starting every step whose dependencies are resolved together
+func (e *Engine) runPipeline(ctx context.Context, execution *protocol.Execution) (string, error) {
+	def, steps, err := e.steps(ctx, execution)
+	if err != nil {
+		return "", err
+	}
+
+	sorted, err := protocol.SortSteps(steps)
+	if err != nil {
+		return "", err
+	}
+
+	execution.TotalSteps = len(sorted)
+
+	p := &pipeline{
+		engine:    e,
+		execution: execution,
+		results:   make(map[string]*protocol.StepResult),
+		excluded:  make(map[string]bool),
+		served:    make(map[string]*model.Model),
+	}
+	if execution.ModelID != "" {
+		p.model, err = e.models.GetVersion(ctx, execution.ModelID, execution.ModelVersion)
+		if err != nil {
+			return "", fmt.Errorf("model %s: %v", execution.ModelID, err)
+		}
+	}
+
+	key, ttl := e.cacheKey(ctx, execution, p.model, def, sorted)
+	if key != "" {
+		if result, ok := e.cached(ctx, key); ok {
+			execution.Cached = true
+			return result, nil
+		}
+	}
+
+	remaining := sorted
+	for len(remaining) > 0 {
+		var ready, waiting []protocol.Step
+		for _, step := range remaining {
+			if p.resolved(step.DependsOn) {
+				ready = append(ready, step)
+			} else {
+				waiting = append(waiting, step)
+			}
+		}
+
+		execution.Running = nil
+		for _, step := range ready {
+			execution.Running = append(execution.Running, step.ID)
+		}
+		e.save(execution)
+
+		var wg sync.WaitGroup
+		for _, step := range ready {
+			wg.Add(1)
+			go func(step protocol.Step) {
+				defer wg.Done()
+				p.runStep(ctx, step)
+			}(step)
+		}
+		wg.Wait()
+		execution.Running = nil
+		e.save(execution)
+
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		for _, step := range ready {
+			if r := p.results[step.ID]; r.Status == protocol.StatusFailed {
+				return "", fmt.Errorf("step %s: %s", step.ID, r.Error)
+			}
+		}
+		remaining = waiting
+	}
+
+	result := p.output(sorted)
+	if key != "" {
+		e.storeCached(ctx, key, ttl, execution, result)
+	}
+	return result, nil
+}
+
+// steps returns the execution's protocol definition, if it has one, and
+// the steps of its pipeline
+func (e *Engine) steps(ctx context.Context, execution *protocol.Execution) (*protocol.Protocol, []protocol.Step, error) {
+	if execution.ProtocolID == "" {
+		return nil, protocol.DefaultSteps(), nil
+	}
+	def, err := e.protocols.Get(ctx, execution.ProtocolID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("protocol %s: %v", execution.ProtocolID, err)
+	}
+	if len(def.Steps) == 0 {
+		return def, protocol.DefaultSteps(), nil
+	}
+	return def, def.Steps, nil
+}
+
+// save writes the execution's state, logging rather than failing the run
+// when the database is unavailable
+func (e *Engine) save(execution *protocol.Execution) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := e.protocols.UpdateExecution(ctx, execution); err != nil {
+		slog.Error("Error saving execution", "execution_id", execution.ID.Hex(), "error", err)
+	}
+}
@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testMongoURIEnv names the environment variable holding the URI of a
+// MongoDB server for the engine tests. They are skipped without it.
+const testMongoURIEnv = "MCP_TEST_MONGODB_URI"
+
+// testDatabase returns a scratch database, dropped when the test ends
+func testDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv(testMongoURIEnv)
+	if uri == "" {
+		t.Skipf("%s not set", testMongoURIEnv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	db := client.Database("mcp_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		db.Drop(ctx)
+		client.Disconnect(ctx)
+	})
+	return db
+}
+
+func TestSubmittedClaimedWithinClass(t *testing.T) {
+	e := New(Config{}, nil, nil, nil, nil)
+	interactive := protocol.PriorityInteractive
+	submit := func(tenant string) string {
+		execution := &protocol.Execution{ID: primitive.NewObjectID(), Priority: interactive, Tenant: tenant}
+		e.Submit(execution)
+		return execution.ID.Hex()
+	}
+	a1, b1, a2 := submit("a"), submit("b"), submit("a")
+	e.Submit(&protocol.Execution{ID: primitive.NewObjectID(), Priority: protocol.PriorityBatch, Tenant: "a"})
+
+	tests := []struct {
+		class protocol.QueueClass
+		want  string
+	}{
+		{class(interactive, "b"), b1},
+		{class(interactive, "a"), a1},
+		{class(interactive, "a"), a2},
+		{class(interactive, "a"), ""},
+		{class(protocol.PriorityBatch, "a"), ""},
+	}
+	for _, tt := range tests {
+		got, ok := e.popSubmitted(tt.class)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("popSubmitted(%v) = %q, %v, want %q", tt.class, got, ok, tt.want)
+		}
+	}
+}
+
+func TestClaimNextSharesSubmittedTenants(t *testing.T) {
+	protocols := protocol.NewProtocolRepository(testDatabase(t))
+	ctx := context.Background()
+	e := New(Config{WorkerID: "worker-1"}, nil, protocols, nil, nil)
+
+	// Tenant a submits its executions to this engine, tenant b to another
+	// one; both are interactive and must share the workers evenly
+	for i := 0; i < 4; i++ {
+		for _, tenant := range []string{"a", "b"} {
+			execution := &protocol.Execution{
+				Status:   protocol.StatusPending,
+				Priority: protocol.PriorityInteractive,
+				Tenant:   tenant,
+				Steps:    []protocol.StepResult{},
+				Running:  []string{},
+			}
+			if err := protocols.CreateExecution(ctx, execution); err != nil {
+				t.Fatalf("CreateExecution: %v", err)
+			}
+			if tenant == "a" {
+				e.Submit(execution)
+			}
+		}
+	}
+
+	var pending map[protocol.QueueClass]int64
+	var tenants []string
+	for i := 0; i < 4; i++ {
+		execution, err := e.claimNext(ctx, &pending)
+		if err != nil || execution == nil {
+			t.Fatalf("claimNext = %v, %v", execution, err)
+		}
+		tenants = append(tenants, execution.Tenant)
+	}
+	counts := map[string]int{}
+	for _, tenant := range tenants {
+		counts[tenant]++
+	}
+	if counts["a"] != 2 || counts["b"] != 2 {
+		t.Errorf("claimed tenants %v, want a and b twice each", tenants)
+	}
+}
+
+func TestPinModels(t *testing.T) {
+	db := testDatabase(t)
+	models := model.NewModelRepository(db)
+	protocols := protocol.NewProtocolRepository(db)
+	ctx := context.Background()
+	e := New(Config{}, models, protocols, nil, nil)
+
+	create := func(m *model.Model) *model.Model {
+		if err := models.Create(ctx, m); err != nil {
+			t.Fatalf("Create %s: %v", m.Name, err)
+		}
+		return m
+	}
+	writer := create(&model.Model{Name: "writer", Type: "gpt-4"})
+	critic := create(&model.Model{Name: "critic", Type: "gpt-4"})
+	panel := create(&model.Model{Name: "panel", Type: model.TypeRouter, Parameters: map[string]string{"models": "critic"}})
+
+	def := &protocol.Protocol{Name: "review", Steps: []protocol.Step{
+		{ID: "draft", Type: protocol.StepModel, Config: map[string]string{"model_id": "writer"}},
+		{ID: "review", Type: protocol.StepModel, DependsOn: []string{"draft"}, Config: map[string]string{"model_id": "panel"}},
+	}}
+	if err := protocols.Create(ctx, def); err != nil {
+		t.Fatalf("Create protocol: %v", err)
+	}
+	execution := &protocol.Execution{ProtocolID: def.ID.Hex()}
+	if err := e.PinModels(ctx, execution); err != nil {
+		t.Fatalf("PinModels: %v", err)
+	}
+
+	want := map[string]string{
+		"writer": writer.ID.Hex() + "@v1",
+		"panel":  panel.ID.Hex() + "@v1",
+		"critic": critic.ID.Hex() + "@v1",
+	}
+	if len(execution.Models) != len(want) {
+		t.Errorf("pinned %v, want %v", execution.Models, want)
+	}
+	for ref, pinned := range want {
+		if pin, ok := execution.Pinned(ref); !ok || pin.String() != pinned {
+			t.Errorf("Pinned(%s) = %v, %v, want %s", ref, pin, ok, pinned)
+		}
+	}
+
+	// A new version of a routed model is not called by the pinned execution
+	if _, err := models.CreateVersion(ctx, critic.ID.Hex(), "gpt-4", "", map[string]string{"temperature": "0"}); err != nil {
+		t.Fatalf("CreateVersion: %v", err)
+	}
+	candidates, err := e.route(ctx, execution, panel, "prompt")
+	if err != nil {
+		t.Fatalf("route: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Version != 1 {
+		t.Errorf("route = %v, want critic at version 1", candidates)
+	}
+	if m, err := e.resolveRef(ctx, &protocol.Execution{}, "critic"); err != nil || m.Version != 2 {
+		t.Errorf("unpinned resolveRef = %v, %v, want critic at version 2", m, err)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	tests := []struct {
+		name      string
+		grace     time.Duration
+		work      time.Duration
+		wantErr   error
+		wantCause error
+	}{
+		{"finishes within the grace period", time.Second, 10 * time.Millisecond, nil, nil},
+		{"returned to the queue after it", 20 * time.Millisecond, time.Minute, context.DeadlineExceeded, errDraining},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(Config{}, nil, nil, nil, nil)
+
+			// An execution started by the claim loop
+			ctx, cancel := context.WithCancelCause(context.Background())
+			e.running["execution"] = cancel
+			e.executing.Add(1)
+			go func() {
+				defer e.executing.Done()
+				select {
+				case <-time.After(tt.work):
+				case <-ctx.Done():
+				}
+			}()
+
+			drainCtx, stop := context.WithTimeout(context.Background(), tt.grace)
+			defer stop()
+			if err := e.Drain(drainCtx); err != tt.wantErr {
+				t.Errorf("Drain = %v, want %v", err, tt.wantErr)
+			}
+			if cause := context.Cause(ctx); cause != tt.wantCause {
+				t.Errorf("execution stopped by %v, want %v", cause, tt.wantCause)
+			}
+			if err := e.Check(); err == nil {
+				t.Error("Check reports a draining engine as taking work")
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	EventDelta  = "delta"  // a chunk of model output
+	EventStep   = "step"   // a step started or finished
+	EventStatus = "status" // the execution changed status
+)
+
+// eventRetention is how long the events of a finished execution stay
+// available to late watchers
+const eventRetention = 5 * time.Minute
+
+// subscriberBuffer is how many events a watcher may lag behind before it
+// is disconnected
+const subscriberBuffer = 1024
+
+// Event is something that happened while an execution ran
+type Event struct {
+	ExecutionID string
+	Sequence    int64
+	Type        string
+	StepID      string
+	Delta       string
+	Status      string
+	Output      string
+	Error       string
+	Time        time.Time
+}
+
+// stream buffers the events of one execution and fans them out to watchers
+type stream struct {
+	events      []Event
+	subscribers map[chan Event]struct{}
+	done        bool
+}
+
+// hub holds the event streams of the executions running in this process
+type hub struct {
+	mu      sync.Mutex
+	streams map[string]*stream
+}
+
+func newHub() *hub {
+	return &hub{
+		streams: make(map[string]*stream),
+	}
+}
+
+// open starts buffering events for an execution. The stream of an earlier
+// run of the execution in this process is replaced.
+func (h *hub) open(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.streams[id]; !ok || s.done {
+		h.streams[id] = &stream{subscribers: make(map[chan Event]struct{})}
+	}
+}
+
+// publish records an event and sends it to the execution's watchers.
+// Watchers that fall too far behind are disconnected rather than blocking
+// the execution; they can reattach and replay the buffer.
+func (h *hub) publish(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.streams[ev.ExecutionID]
+	if !ok || s.done {
+		return
+	}
+
+	ev.Sequence = int64(len(s.events) + 1)
+	ev.Time = time.Now()
+	s.events = append(s.events, ev)
+	for ch := range s.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// close ends an execution's stream and forgets it after eventRetention
+func (h *hub) close(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.streams[id]
+	if !ok {
+		return
+	}
+	s.done = true
+	for ch := range s.subscribers {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
+
+	time.AfterFunc(eventRetention, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.streams[id] == s {
+			delete(h.streams, id)
+		}
+	})
+}
+
+// subscribe returns the events buffered so far and a channel of the ones
+// that follow, which is closed when the execution finishes
+func (h *hub) subscribe(id string) ([]Event, <-chan Event, func(), bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.streams[id]
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	replay := append([]Event(nil), s.events...)
+	ch := make(chan Event, subscriberBuffer)
+	if s.done {
+		close(ch)
+		return replay, ch, func() {}, true
+	}
+
+	s.subscribers[ch] = struct{}{}
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := s.subscribers[ch]; ok {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+	return replay, ch, cancel, true
+}
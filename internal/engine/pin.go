@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+)
+
+// PinModels pins every model the execution's pipeline can call, those the
+// steps set by "model_id" and the models routers among them route to, to
+// the version each reference resolves to now. The pipeline calls the
+// pinned versions however the references move afterwards, including when
+// the execution is claimed again.
+func (e *Engine) PinModels(ctx context.Context, execution *protocol.Execution) error {
+	_, steps, err := e.steps(ctx, execution)
+	if err != nil {
+		return err
+	}
+	var m *model.Model
+	if execution.ModelID != "" {
+		if m, err = e.models.GetVersion(ctx, execution.ModelID, execution.ModelVersion); err != nil {
+			return fmt.Errorf("model %s: %v", execution.ModelID, err)
+		}
+	}
+
+	execution.Models = nil
+	var pins []protocol.ModelPin
+	err = e.eachModel(ctx, execution, m, steps, func(ref string, m *model.Model) {
+		if ref == "" {
+			return
+		}
+		for _, pin := range pins {
+			if pin.Ref == ref {
+				return
+			}
+		}
+		pins = append(pins, protocol.ModelPin{Ref: ref, ModelID: m.ID.Hex(), Version: m.Version})
+	})
+	if err != nil {
+		return err
+	}
+	execution.Models = pins
+	return nil
+}
+
+// resolveRef returns the model version a reference made by the pipeline
+// points to: the version pinned on the execution, or for executions
+// planned without pins, the reference's current version
+func (e *Engine) resolveRef(ctx context.Context, execution *protocol.Execution, ref string) (*model.Model, error) {
+	if pin, ok := execution.Pinned(ref); ok {
+		return e.models.GetVersion(ctx, pin.ModelID, pin.Version)
+	}
+	return e.models.Resolve(ctx, ref)
+}
+
+// eachModel calls fn with every model the pipeline can call and the
+// reference it is called by: the execution's model m, with an empty
+// reference, those set by the steps' "model_id", and the models routers
+// among them route to
+func (e *Engine) eachModel(ctx context.Context, execution *protocol.Execution, m *model.Model, steps []protocol.Step, fn func(ref string, m *model.Model)) error {
+	seen := make(map[string]bool)
+	var add func(ref string, m *model.Model) error
+	add = func(ref string, m *model.Model) error {
+		fn(ref, m)
+		key := fmt.Sprintf("%s@v%d", m.ID.Hex(), m.Version)
+		if seen[key] || !m.IsRouter() {
+			return nil
+		}
+		seen[key] = true
+		for _, route := range m.Routes() {
+			routed, err := e.resolveRef(ctx, execution, route)
+			if err != nil {
+				return fmt.Errorf("router %s: model %s: %v", m.Name, route, err)
+			}
+			if err := add(route, routed); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if m != nil {
+		if err := add("", m); err != nil {
+			return err
+		}
+	}
+	for _, step := range steps {
+		ref := step.Config["model_id"]
+		if ref == "" {
+			continue
+		}
+		stepModel, err := e.resolveRef(ctx, execution, ref)
+		if err != nil {
+			return fmt.Errorf("step %s: model %s: %v", step.ID, ref, err)
+		}
+		if err := add(ref, stepModel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,564 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/provider"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/data"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultPrompt is sent to the model when a step does not set "prompt"
+const defaultPrompt = "{{if .Context}}{{.Context}}\n\n{{end}}{{.Input}}"
+
+// pipeline holds the state of one execution's steps while they run
+type pipeline struct {
+	engine    *Engine
+	execution *protocol.Execution
+	model     *model.Model
+
+	mu       sync.Mutex
+	results  map[string]*protocol.StepResult
+	excluded map[string]bool         // steps on the untaken side of a branch
+	served   map[string]*model.Model // model version that served each model step
+}
+
+// templateData is what step templates see
+type templateData struct {
+	Input   string
+	Context string
+	Params  map[string]string
+	Steps   map[string]string
+	Item    string
+	Index   int
+}
+
+var templateFuncs = template.FuncMap{
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"trim":     strings.TrimSpace,
+	"contains": strings.Contains,
+	"replace":  strings.ReplaceAll,
+	"split":    strings.Split,
+	"join":     strings.Join,
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// resolved reports whether all the given steps have finished
+func (p *pipeline) resolved(ids []string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, id := range ids {
+		if _, ok := p.results[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// runStep runs a single step and records its result on the execution
+func (p *pipeline) runStep(ctx context.Context, step protocol.Step) {
+	ctx, span := tracer.Start(ctx, "step "+step.ID, trace.WithAttributes(
+		attribute.String("mcp.step.id", step.ID),
+		attribute.String("mcp.step.type", step.Type),
+	))
+	defer span.End()
+
+	result := &protocol.StepResult{
+		ID:        step.ID,
+		Type:      step.Type,
+		StartedAt: time.Now(),
+	}
+
+	if p.skip(step) {
+		result.Status = protocol.StatusSkipped
+	} else {
+		p.publishStep(step.ID, protocol.StatusRunning, "", "")
+		output, err := p.execute(ctx, step)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			result.Status = protocol.StatusFailed
+			result.Error = err.Error()
+		} else {
+			result.Status = protocol.StatusCompleted
+			result.Output = output
+		}
+	}
+	result.FinishedAt = time.Now()
+	span.SetAttributes(attribute.String("mcp.step.status", result.Status))
+
+	p.mu.Lock()
+	if m := p.served[step.ID]; m != nil {
+		result.ModelID, result.ModelVersion = m.ID.Hex(), m.Version
+	}
+	p.results[step.ID] = result
+	p.execution.Steps = append(p.execution.Steps, *result)
+	p.mu.Unlock()
+
+	p.publishStep(step.ID, result.Status, result.Output, result.Error)
+}
+
+// publishStep emits a step transition to the execution's watchers
+func (p *pipeline) publishStep(stepID, status, output, errMsg string) {
+	p.engine.events.publish(Event{
+		ExecutionID: p.execution.ID.Hex(),
+		Type:        EventStep,
+		StepID:      stepID,
+		Status:      status,
+		Output:      output,
+		Error:       errMsg,
+	})
+}
+
+// skip reports whether a step must be skipped: when a branch excluded it,
+// when one of its dependencies was skipped, or for fan-in steps, when all
+// of them were
+func (p *pipeline) skip(step protocol.Step) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.excluded[step.ID] {
+		return true
+	}
+
+	skipped := 0
+	for _, dep := range step.DependsOn {
+		if p.results[dep].Status == protocol.StatusSkipped {
+			skipped++
+		}
+	}
+	if step.Type == protocol.StepFanIn {
+		return len(step.DependsOn) > 0 && skipped == len(step.DependsOn)
+	}
+	return skipped > 0
+}
+
+// execute runs a step according to its type and returns its output
+func (p *pipeline) execute(ctx context.Context, step protocol.Step) (string, error) {
+	switch step.Type {
+	case protocol.StepModel:
+		_, span := tracer.Start(ctx, "render prompt")
+		prompt, err := p.render(step.Config["prompt"], defaultPrompt, "", 0)
+		span.End()
+		if err != nil {
+			return "", err
+		}
+		return p.callModel(ctx, step, prompt, true)
+
+	case protocol.StepRender:
+		return p.render(step.Config["template"], p.execution.Context, "", 0)
+
+	case protocol.StepTransform:
+		if step.Config["template"] == "" {
+			return "", fmt.Errorf("transform step needs a template")
+		}
+		return p.render(step.Config["template"], "", "", 0)
+
+	case protocol.StepBranch:
+		return p.branch(step)
+
+	case protocol.StepFanOut:
+		return p.fanOut(ctx, step)
+
+	case protocol.StepFanIn:
+		if step.Config["template"] != "" {
+			return p.render(step.Config["template"], "", "", 0)
+		}
+		return p.joinDependencies(step), nil
+
+	case protocol.StepStore:
+		return p.store(ctx, step)
+
+	default:
+		return "", fmt.Errorf("unknown step type %q", step.Type)
+	}
+}
+
+// render executes a step template, using def when text is empty
+func (p *pipeline) render(text, def, item string, index int) (string, error) {
+	if text == "" {
+		text = def
+	}
+
+	tmpl, err := template.New("step").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	steps := make(map[string]string, len(p.results))
+	for id, r := range p.results {
+		steps[id] = r.Output
+	}
+	p.mu.Unlock()
+
+	var out strings.Builder
+	err = tmpl.Execute(&out, templateData{
+		Input:   p.execution.Input,
+		Context: p.execution.Context,
+		Params:  p.execution.Metadata,
+		Steps:   steps,
+		Item:    item,
+		Index:   index,
+	})
+	return out.String(), err
+}
+
+// callModel sends a prompt to the step's model: the version pinned for the
+// "model_id" config value, or else the execution's model. Router models
+// fail over between the models they route to. With stream set, providers
+// that can stream publish their output as delta events.
+func (p *pipeline) callModel(ctx context.Context, step protocol.Step, prompt string, stream bool) (string, error) {
+	m := p.model
+	if id := step.Config["model_id"]; id != "" {
+		var err error
+		if m, err = p.engine.resolveRef(ctx, p.execution, id); err != nil {
+			return "", fmt.Errorf("model %s: %v", id, err)
+		}
+	}
+	if m == nil {
+		return "", fmt.Errorf("no model to call")
+	}
+	if !m.IsRouter() {
+		return p.complete(ctx, step, m, prompt, stream)
+	}
+
+	candidates, err := p.engine.route(ctx, p.execution, m, prompt)
+	if err != nil {
+		return "", err
+	}
+	var errs []string
+	for _, candidate := range candidates {
+		output, err := p.complete(ctx, step, candidate, prompt, stream)
+		if err == nil {
+			return output, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", candidate.Name, err))
+	}
+	return "", fmt.Errorf("all models of router %s failed: %s", m.Name, strings.Join(errs, "; "))
+}
+
+// complete sends a prompt to a model's provider and records the model
+// version as the one that served the step
+func (p *pipeline) complete(ctx context.Context, step protocol.Step, m *model.Model, prompt string, stream bool) (string, error) {
+	prov, err := p.engine.providers.ForModel(m)
+	if err != nil {
+		return "", err
+	}
+	apiKey, err := p.engine.providers.APIKey(ctx, m)
+	if err != nil {
+		return "", err
+	}
+
+	req := &provider.Request{
+		Model:      m,
+		Type:       p.execution.Type,
+		Prompt:     prompt,
+		Parameters: mergeParams(m, p.execution.Metadata),
+		APIKey:     apiKey,
+	}
+
+	call := prov.Complete
+	if streamer, ok := prov.(provider.Streamer); ok && stream {
+		call = func(ctx context.Context, req *provider.Request) (*provider.Response, error) {
+			return streamer.Stream(ctx, req, func(delta string) {
+				p.engine.events.publish(Event{
+					ExecutionID: p.execution.ID.Hex(),
+					Type:        EventDelta,
+					StepID:      step.ID,
+					Delta:       delta,
+				})
+			})
+		}
+	}
+
+	resp, err := p.withRetries(ctx, step, m, func() (*provider.Response, error) {
+		release, err := p.engine.limits.acquire(ctx, prov.Name())
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		ctx, span := tracer.Start(ctx, "provider "+prov.Name(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("mcp.provider", prov.Name()),
+				attribute.String("mcp.model.id", m.ID.Hex()),
+				attribute.String("mcp.model.name", provider.ModelName(m)),
+			))
+		defer span.End()
+
+		started := time.Now()
+		resp, err := call(ctx, req)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		var promptTokens, completionTokens int
+		if resp != nil {
+			promptTokens, completionTokens = resp.PromptTokens, resp.CompletionTokens
+			span.SetAttributes(
+				attribute.Int("mcp.tokens.prompt", promptTokens),
+				attribute.Int("mcp.tokens.completion", completionTokens),
+			)
+		}
+		p.engine.cfg.Metrics.ObserveProvider(prov.Name(), provider.ModelName(m), time.Since(started), promptTokens, completionTokens, err)
+		return resp, err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	p.served[step.ID] = m
+	p.execution.ServedBy = m.ID.Hex()
+	p.execution.ServedByVersion = m.Version
+	p.mu.Unlock()
+	return resp.Output, nil
+}
+
+// withRetries makes a provider call, retrying retryable failures with
+// exponential backoff up to the engine's retry attempts, and records every
+// attempt on the execution
+func (p *pipeline) withRetries(ctx context.Context, step protocol.Step, m *model.Model, call func() (*provider.Response, error)) (*provider.Response, error) {
+	const maxBackoff = 30 * time.Second
+	backoff := p.engine.cfg.RetryBackoff
+
+	for number := 1; ; number++ {
+		attempt := protocol.Attempt{
+			StepID:       step.ID,
+			ModelID:      m.ID.Hex(),
+			ModelVersion: m.Version,
+			Number:       number,
+			StartedAt:    time.Now(),
+		}
+		resp, err := call()
+		attempt.FinishedAt = time.Now()
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+		if resp != nil {
+			attempt.Usage = protocol.Usage{
+				PromptTokens:     resp.PromptTokens,
+				CompletionTokens: resp.CompletionTokens,
+				Cost:             m.Cost(resp.PromptTokens, resp.CompletionTokens),
+			}
+		}
+
+		p.mu.Lock()
+		p.execution.Attempts = append(p.execution.Attempts, attempt)
+		p.execution.Usage.Add(attempt.Usage)
+		p.mu.Unlock()
+
+		if err == nil {
+			return resp, nil
+		}
+		if number > p.engine.cfg.RetryAttempts || !provider.Retryable(err) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// branch evaluates the "condition" template and excludes the steps listed
+// under "else" when it holds, or under "then" when it does not
+func (p *pipeline) branch(step protocol.Step) (string, error) {
+	value, err := p.render(step.Config["condition"], "", "", 0)
+	if err != nil {
+		return "", err
+	}
+
+	taken := truthy(value)
+	excluded := step.Targets("then")
+	if taken {
+		excluded = step.Targets("else")
+	}
+
+	p.mu.Lock()
+	for _, id := range excluded {
+		p.excluded[id] = true
+	}
+	p.mu.Unlock()
+
+	return strconv.FormatBool(taken), nil
+}
+
+// fanOut calls the model once per item and returns the outputs as a JSON
+// array. Items come from the "items" template, split on "separator" (a
+// newline by default) or parsed as a JSON array when "format" is json.
+func (p *pipeline) fanOut(ctx context.Context, step protocol.Step) (string, error) {
+	list, err := p.render(step.Config["items"], "{{.Input}}", "", 0)
+	if err != nil {
+		return "", err
+	}
+
+	var items []string
+	if step.Config["format"] == "json" {
+		if err := json.Unmarshal([]byte(list), &items); err != nil {
+			return "", fmt.Errorf("items are not a JSON array of strings: %v", err)
+		}
+	} else {
+		separator := step.Config["separator"]
+		if separator == "" {
+			separator = "\n"
+		}
+		for _, item := range strings.Split(list, separator) {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+
+	concurrency, err := strconv.Atoi(step.Config["concurrency"])
+	if err != nil || concurrency <= 0 {
+		concurrency = 4
+	}
+
+	outputs := make([]string, len(items))
+	errs := make([]error, len(items))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			prompt, err := p.render(step.Config["prompt"], "{{.Item}}", item, i)
+			if err == nil {
+				outputs[i], err = p.callModel(ctx, step, prompt, false)
+			}
+			errs[i] = err
+		}(i, item)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return "", fmt.Errorf("item %d: %v", i, err)
+		}
+	}
+
+	out, err := json.Marshal(outputs)
+	return string(out), err
+}
+
+// joinDependencies joins the outputs of the dependencies that completed
+func (p *pipeline) joinDependencies(step protocol.Step) string {
+	separator := step.Config["separator"]
+	if separator == "" {
+		separator = "\n\n"
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var outputs []string
+	for _, dep := range step.DependsOn {
+		if r := p.results[dep]; r.Status == protocol.StatusCompleted {
+			outputs = append(outputs, r.Output)
+		}
+	}
+	return strings.Join(outputs, separator)
+}
+
+// store saves the "template" result, or the joined dependency outputs, as
+// data of type "data_type" and returns the new data ID
+func (p *pipeline) store(ctx context.Context, step protocol.Step) (string, error) {
+	content := p.joinDependencies(step)
+	if step.Config["template"] != "" {
+		var err error
+		if content, err = p.render(step.Config["template"], "", "", 0); err != nil {
+			return "", err
+		}
+	}
+
+	dataType := step.Config["data_type"]
+	if dataType == "" {
+		dataType = "TEXT"
+	}
+
+	d := &data.Data{
+		Type:    dataType,
+		Content: content,
+		Metadata: map[string]string{
+			"execution_id": p.execution.ID.Hex(),
+			"step":         step.ID,
+		},
+	}
+	if err := p.engine.data.Add(ctx, d); err != nil {
+		return "", err
+	}
+	return d.ID.Hex(), nil
+}
+
+// output returns the execution result: the output of the step named by the
+// "output_step" parameter, or else of the last step that completed
+func (p *pipeline) output(sorted []protocol.Step) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if id := p.execution.Metadata["output_step"]; id != "" {
+		if r, ok := p.results[id]; ok {
+			return r.Output
+		}
+	}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		if r := p.results[sorted[i].ID]; r.Status == protocol.StatusCompleted {
+			return r.Output
+		}
+	}
+	return ""
+}
+
+// mergeParams overlays execution parameters on model parameters, except
+// the model-only ones: an execution must never send the model's
+// credentials to an endpoint of its choosing
+func mergeParams(m *model.Model, overrides map[string]string) map[string]string {
+	schema := model.SchemaFor(m.Type)
+	merged := make(map[string]string, len(m.Parameters)+len(overrides))
+	for k, v := range m.Parameters {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		if !schema[k].ModelOnly {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// truthy interprets a rendered branch condition
+func truthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "false", "0", "no":
+		return false
+	default:
+		return true
+	}
+}
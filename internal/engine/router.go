@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/tokenizer"
+)
+
+// Routing policies of router models, set by their "routing" parameter
+const (
+	// RouteFallback tries the models in the order they are listed
+	RouteFallback = "fallback"
+	// RouteCheapest tries the models from the lowest price per token
+	RouteCheapest = "cheapest"
+	// RouteInputLength tries the models from the smallest context window
+	// the prompt fits in, leaving out those it does not fit
+	RouteInputLength = "input_length"
+	// RouteRoundRobin starts from the next model on every call
+	RouteRoundRobin = "round_robin"
+)
+
+// RoutingPolicies lists the valid routing policies
+var RoutingPolicies = []string{RouteFallback, RouteCheapest, RouteInputLength, RouteRoundRobin}
+
+// rotation hands out the round-robin starting points of router models
+type rotation struct {
+	mu   sync.Mutex
+	next map[string]int
+}
+
+func newRotation() *rotation {
+	return &rotation{next: make(map[string]int)}
+}
+
+// advance returns the starting point of the next call through a router
+// with n models
+func (r *rotation) advance(routerID string, n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	start := r.next[routerID] % n
+	r.next[routerID] = start + 1
+	return start
+}
+
+// route returns the models a router tries for a prompt, at the versions
+// pinned on the execution, in the order its routing policy gives, leaving
+// out unhealthy models unless all of them are. Calls fail over to the next
+// model when one fails.
+func (e *Engine) route(ctx context.Context, execution *protocol.Execution, router *model.Model, prompt string) ([]*model.Model, error) {
+	ids := router.Routes()
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("router %s lists no models", router.Name)
+	}
+
+	candidates := make([]*model.Model, 0, len(ids))
+	for _, id := range ids {
+		m, err := e.resolveRef(ctx, execution, id)
+		if err != nil {
+			return nil, fmt.Errorf("router %s: model %s: %v", router.Name, id, err)
+		}
+		if m.IsRouter() {
+			return nil, fmt.Errorf("router %s: model %s is itself a router", router.Name, id)
+		}
+		candidates = append(candidates, m)
+	}
+
+	switch policy := router.Parameters["routing"]; policy {
+	case "", RouteFallback:
+	case RouteCheapest:
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].Cost(1000, 1000) < candidates[j].Cost(1000, 1000)
+		})
+	case RouteInputLength:
+		var fitting []*model.Model
+		for _, m := range candidates {
+			budget := m.PromptBudget()
+			if budget == 0 || tokenizer.ForModel(m.Type, m.Parameters).Count(prompt) <= budget {
+				fitting = append(fitting, m)
+			}
+		}
+		if len(fitting) == 0 {
+			return nil, fmt.Errorf("router %s: prompt fits no model's context window", router.Name)
+		}
+		// Models with unknown windows go last
+		sort.SliceStable(fitting, func(i, j int) bool {
+			a, b := fitting[i].ContextWindow(), fitting[j].ContextWindow()
+			return a != 0 && (b == 0 || a < b)
+		})
+		candidates = fitting
+	case RouteRoundRobin:
+		start := e.rotation.advance(router.ID.Hex(), len(candidates))
+		rotated := make([]*model.Model, 0, len(candidates))
+		rotated = append(rotated, candidates[start:]...)
+		candidates = append(rotated, candidates[:start]...)
+	default:
+		return nil, fmt.Errorf("router %s: invalid routing policy %q, must be one of %v", router.Name, policy, RoutingPolicies)
+	}
+	return e.healthy(candidates), nil
+}
+
+// healthy leaves out the unhealthy models, or none when all of them are.
+// Only routers consult health: a model named directly by an execution or
+// a step's "model_id" is called whatever its health.
+func (e *Engine) healthy(candidates []*model.Model) []*model.Model {
+	if e.cfg.Healthy == nil {
+		return candidates
+	}
+
+	var healthy []*model.Model
+	for _, m := range candidates {
+		if e.cfg.Healthy(m.ID.Hex()) {
+			healthy = append(healthy, m)
+		}
+	}
+	if len(healthy) == 0 {
+		return candidates
+	}
+	return healthy
+}
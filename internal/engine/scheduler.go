@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"context"
+	"sync"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+)
+
+// DefaultWeights are the shares of the workers given to each priority
+var DefaultWeights = map[string]int{
+	protocol.PriorityInteractive: 4,
+	protocol.PriorityBatch:       1,
+}
+
+// scheduler orders queue classes by weighted fair queuing in two levels:
+// the priority to claim from is picked by the weights, whatever the number
+// of its tenants, then the tenant is picked evenly among the priority's
+// tenants with pending work. At each level a claim is tagged with a virtual
+// finish time that advances by its cost, 1/weight for priorities and 1 for
+// tenants, and the earliest tag is claimed from first.
+type scheduler struct {
+	weights map[string]int
+
+	mu         sync.Mutex
+	priorities fairQueue
+	tenants    map[string]*fairQueue // keyed by priority
+}
+
+// fairQueue holds the virtual times of the flows sharing one level
+type fairQueue struct {
+	clock  float64            // start time of the last claim
+	finish map[string]float64 // finish time of each flow's last claim
+	active map[string]bool    // flows with pending work at the last pick
+}
+
+// backlog updates the flows with pending work. Flows that were idle start
+// at the current clock rather than catching up on the time they were idle;
+// flows that stay backlogged keep their place.
+func (q *fairQueue) backlog(flows map[string]bool) {
+	if q.finish == nil {
+		q.finish = make(map[string]float64)
+	}
+	for flow := range flows {
+		if !q.active[flow] && q.finish[flow] < q.clock {
+			q.finish[flow] = q.clock
+		}
+	}
+	q.active = flows
+}
+
+// tag is the virtual finish time of flow's next claim
+func (q *fairQueue) tag(flow string, cost float64) float64 {
+	return q.finish[flow] + cost
+}
+
+// advance records a claim of the given cost from flow
+func (q *fairQueue) advance(flow string, cost float64) {
+	if q.finish == nil {
+		q.finish = make(map[string]float64)
+	}
+	q.clock = q.finish[flow]
+	q.finish[flow] += cost
+}
+
+func newScheduler(weights map[string]int) *scheduler {
+	if len(weights) == 0 {
+		weights = DefaultWeights
+	}
+	return &scheduler{
+		weights: weights,
+		tenants: make(map[string]*fairQueue),
+	}
+}
+
+// cost is the virtual time a claim from priority takes
+func (s *scheduler) cost(priority string) float64 {
+	weight := s.weights[priority]
+	if weight <= 0 {
+		weight = s.weights[protocol.PriorityInteractive]
+	}
+	if weight <= 0 {
+		weight = 1
+	}
+	return 1 / float64(weight)
+}
+
+// next returns the class with pending work to claim from next
+func (s *scheduler) next(pending map[protocol.QueueClass]int64) (protocol.QueueClass, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	priorities := make(map[string]bool)
+	tenants := make(map[string]map[string]bool)
+	for c, n := range pending {
+		if n <= 0 {
+			continue
+		}
+		priorities[c.Priority] = true
+		if tenants[c.Priority] == nil {
+			tenants[c.Priority] = make(map[string]bool)
+		}
+		tenants[c.Priority][c.Tenant] = true
+	}
+	s.priorities.backlog(priorities)
+	for priority, q := range s.tenants {
+		if tenants[priority] == nil {
+			q.backlog(nil)
+		}
+	}
+	if len(priorities) == 0 {
+		return protocol.QueueClass{}, false
+	}
+
+	// Pick the priority first, so that its share does not depend on how
+	// many tenants it has
+	var priority string
+	var bestTag float64
+	for p := range priorities {
+		tag := s.priorities.tag(p, s.cost(p))
+		if priority == "" || tag < bestTag || (tag == bestTag && lessPriority(p, priority)) {
+			priority, bestTag = p, tag
+		}
+	}
+
+	// Then the tenant, evenly within the priority
+	q := s.tenantQueue(priority)
+	q.backlog(tenants[priority])
+	best := protocol.QueueClass{Priority: priority}
+	found := false
+	for tenant := range tenants[priority] {
+		tag := q.tag(tenant, 1)
+		if !found || tag < bestTag || (tag == bestTag && tenant < best.Tenant) {
+			best.Tenant, bestTag, found = tenant, tag, true
+		}
+	}
+	return best, true
+}
+
+// claimed advances the virtual times of class c after a claim from it
+func (s *scheduler) claimed(c protocol.QueueClass) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.priorities.advance(c.Priority, s.cost(c.Priority))
+	s.tenantQueue(c.Priority).advance(c.Tenant, 1)
+}
+
+func (s *scheduler) tenantQueue(priority string) *fairQueue {
+	q, ok := s.tenants[priority]
+	if !ok {
+		q = &fairQueue{}
+		s.tenants[priority] = q
+	}
+	return q
+}
+
+// lessPriority orders priorities deterministically when their tags tie
+func lessPriority(a, b string) bool {
+	if a != b && (a == protocol.PriorityInteractive || b == protocol.PriorityInteractive) {
+		return a == protocol.PriorityInteractive
+	}
+	return a < b
+}
+
+// limiter bounds the concurrent calls to each provider
+type limiter struct {
+	limits map[string]int
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newLimiter(limits map[string]int) *limiter {
+	return &limiter{
+		limits: limits,
+		slots:  make(map[string]chan struct{}),
+	}
+}
+
+// acquire waits for a free call slot of the named provider. Providers
+// without a limit are not bounded.
+func (l *limiter) acquire(ctx context.Context, name string) (release func(), err error) {
+	if l.limits[name] <= 0 {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	slots, ok := l.slots[name]
+	if !ok {
+		slots = make(chan struct{}, l.limits[name])
+		l.slots[name] = slots
+	}
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
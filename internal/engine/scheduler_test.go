@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+)
+
+func class(priority, tenant string) protocol.QueueClass {
+	return protocol.QueueClass{Priority: priority, Tenant: tenant}
+}
+
+// claim runs n claims against a backlog that never empties and counts the
+// claims of each class
+func claim(s *scheduler, pending map[protocol.QueueClass]int64, n int) map[protocol.QueueClass]int {
+	counts := make(map[protocol.QueueClass]int)
+	for i := 0; i < n; i++ {
+		c, ok := s.next(pending)
+		if !ok {
+			break
+		}
+		s.claimed(c)
+		counts[c]++
+	}
+	return counts
+}
+
+func TestSchedulerShares(t *testing.T) {
+	interactive := protocol.PriorityInteractive
+	batch := protocol.PriorityBatch
+
+	tests := []struct {
+		name    string
+		weights map[string]int
+		pending []protocol.QueueClass
+		claims  int
+		want    map[protocol.QueueClass]int
+	}{
+		{
+			name:    "priorities by weight",
+			pending: []protocol.QueueClass{class(interactive, "a"), class(batch, "a")},
+			claims:  50,
+			want:    map[protocol.QueueClass]int{class(interactive, "a"): 40, class(batch, "a"): 10},
+		},
+		{
+			name: "many batch tenants share the batch weight",
+			pending: []protocol.QueueClass{
+				class(interactive, "a"),
+				class(batch, "b"), class(batch, "c"), class(batch, "d"), class(batch, "e"),
+			},
+			claims: 50,
+			want: map[protocol.QueueClass]int{
+				class(interactive, "a"): 40,
+				class(batch, "b"):       3, class(batch, "c"): 3, class(batch, "d"): 2, class(batch, "e"): 2,
+			},
+		},
+		{
+			name: "tenants of a priority share evenly",
+			pending: []protocol.QueueClass{
+				class(interactive, "a"), class(interactive, "b"),
+				class(batch, "c"),
+			},
+			claims: 50,
+			want: map[protocol.QueueClass]int{
+				class(interactive, "a"): 20, class(interactive, "b"): 20,
+				class(batch, "c"): 10,
+			},
+		},
+		{
+			name:    "custom weights",
+			weights: map[string]int{interactive: 1, batch: 1},
+			pending: []protocol.QueueClass{class(interactive, "a"), class(batch, "a"), class(batch, "b")},
+			claims:  40,
+			want:    map[protocol.QueueClass]int{class(interactive, "a"): 20, class(batch, "a"): 10, class(batch, "b"): 10},
+		},
+		{
+			name:    "single class takes every claim",
+			pending: []protocol.QueueClass{class(batch, "a")},
+			claims:  10,
+			want:    map[protocol.QueueClass]int{class(batch, "a"): 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pending := make(map[protocol.QueueClass]int64)
+			for _, c := range tt.pending {
+				pending[c] = 1000
+			}
+			got := claim(newScheduler(tt.weights), pending, tt.claims)
+			for c, want := range tt.want {
+				if got[c] != want {
+					t.Errorf("%s/%s claimed %d times, want %d", c.Priority, c.Tenant, got[c], want)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("claimed from %d classes, want %d", len(got), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSchedulerOrder(t *testing.T) {
+	interactive := protocol.PriorityInteractive
+	batch := protocol.PriorityBatch
+	s := newScheduler(nil)
+	pending := map[protocol.QueueClass]int64{
+		class(interactive, "a"): 10,
+		class(batch, "b"):       10,
+		class(batch, "c"):       10,
+	}
+
+	var got []protocol.QueueClass
+	for i := 0; i < 10; i++ {
+		c, _ := s.next(pending)
+		s.claimed(c)
+		got = append(got, c)
+	}
+	want := []protocol.QueueClass{
+		class(interactive, "a"), class(interactive, "a"), class(interactive, "a"), class(interactive, "a"),
+		class(batch, "b"),
+		class(interactive, "a"), class(interactive, "a"), class(interactive, "a"), class(interactive, "a"),
+		class(batch, "c"),
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("claim %d from %v, want %v (order %v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestSchedulerIdleClass(t *testing.T) {
+	interactive := protocol.PriorityInteractive
+	batch := protocol.PriorityBatch
+	s := newScheduler(nil)
+
+	// Batch runs alone for a while
+	claim(s, map[protocol.QueueClass]int64{class(batch, "a"): 100}, 20)
+
+	// Interactive arriving later does not get to catch up on the time it
+	// was idle: it gets its share, give or take the claim batch is into
+	counts := claim(s, map[protocol.QueueClass]int64{
+		class(interactive, "b"): 100,
+		class(batch, "a"):       100,
+	}, 50)
+	if n := counts[class(batch, "a")]; n < 9 || n > 10 {
+		t.Errorf("claims = %v, want about 40 interactive and 10 batch", counts)
+	}
+}
+
+func TestSchedulerNothingPending(t *testing.T) {
+	s := newScheduler(nil)
+	pending := map[protocol.QueueClass]int64{class(protocol.PriorityBatch, "a"): 0}
+	if c, ok := s.next(pending); ok {
+		t.Errorf("next = %v, want none", c)
+	}
+}
@@ -0,0 +1,169 @@
+package healthcheck
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Components reported as services of the standard gRPC health service,
+// alongside the server as a whole ("") and the services it serves
+const (
+	MongoDB   = "mongodb"
+	Workers   = "workers"
+	Providers = "providers"
+)
+
+// Components lists the subsystems whose health is checked
+var Components = []string{MongoDB, Workers, Providers}
+
+// Check reports an error when a component is unhealthy
+type Check func(ctx context.Context) error
+
+// Config controls how often components are checked
+type Config struct {
+	Interval time.Duration // how often the components are checked, 10s by default
+	Timeout  time.Duration // deadline of each check, 5s by default
+	Services []string      // gRPC services served while all components are healthy
+}
+
+// Checker runs the health checks of the server's components and publishes
+// their status through the standard gRPC health service. The server and
+// its services are serving only while every component is.
+type Checker struct {
+	cfg    Config
+	server *health.Server
+
+	mu       sync.Mutex
+	checks   map[string]Check
+	statuses map[string]healthpb.HealthCheckResponse_ServingStatus
+	stopped  bool
+}
+
+// New creates a Checker. Components are reported NOT_SERVING until their
+// first check.
+func New(cfg Config) *Checker {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	c := &Checker{
+		cfg:      cfg,
+		server:   health.NewServer(),
+		checks:   make(map[string]Check),
+		statuses: make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+	}
+	c.server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	for _, service := range cfg.Services {
+		c.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return c
+}
+
+// Server returns the gRPC health service to register
+func (c *Checker) Server() healthpb.HealthServer {
+	return c.server
+}
+
+// Add registers the check of a component
+func (c *Checker) Add(component string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[component] = check
+	c.server.SetServingStatus(component, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// Start checks the components right away and then periodically until ctx
+// is done
+func (c *Checker) Start(ctx context.Context) {
+	c.checkAll(ctx)
+	go func() {
+		ticker := time.NewTicker(c.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.checkAll(ctx)
+			}
+		}
+	}()
+}
+
+// Shutdown reports everything NOT_SERVING from now on, so that load
+// balancers stop sending requests to a server about to stop
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopped = true
+	c.server.Shutdown()
+}
+
+// checkAll runs every check concurrently and publishes the outcome
+func (c *Checker) checkAll(ctx context.Context) {
+	c.mu.Lock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.Unlock()
+
+	var wg sync.WaitGroup
+	var resultsMu sync.Mutex
+	results := make(map[string]error, len(checks))
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+			defer cancel()
+			err := check(checkCtx)
+
+			resultsMu.Lock()
+			results[name] = err
+			resultsMu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return
+	}
+
+	overall := healthpb.HealthCheckResponse_SERVING
+	for name, err := range results {
+		status := healthpb.HealthCheckResponse_SERVING
+		if err != nil {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+			overall = status
+		}
+		if previous, ok := c.statuses[name]; !ok || previous != status {
+			if err != nil {
+				slog.Warn("Component health changed", "component", name, "status", status.String(), "error", err)
+			} else {
+				slog.Info("Component health changed", "component", name, "status", status.String())
+			}
+		}
+		c.statuses[name] = status
+		c.server.SetServingStatus(name, status)
+	}
+
+	c.server.SetServingStatus("", overall)
+	for _, service := range c.cfg.Services {
+		c.server.SetServingStatus(service, overall)
+	}
+}
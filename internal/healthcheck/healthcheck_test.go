@@ -0,0 +1,95 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	serving    = healthpb.HealthCheckResponse_SERVING
+	notServing = healthpb.HealthCheckResponse_NOT_SERVING
+)
+
+// status returns the status the health service reports for service
+func status(t *testing.T, c *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := c.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q): %v", service, err)
+	}
+	return resp.Status
+}
+
+// expect checks the reported status of each service
+func expect(t *testing.T, c *Checker, want map[string]healthpb.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+	for service, w := range want {
+		if got := status(t, c, service); got != w {
+			t.Errorf("%q is %s, want %s", service, got, w)
+		}
+	}
+}
+
+func TestChecker(t *testing.T) {
+	const service = "proto.MCPService"
+	c := New(Config{Timeout: 50 * time.Millisecond, Services: []string{service}})
+
+	var mongoErr error
+	c.Add(MongoDB, func(context.Context) error { return mongoErr })
+	c.Add(Workers, func(context.Context) error { return nil })
+
+	// Nothing is serving before the first check
+	expect(t, c, map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"": notServing, service: notServing, MongoDB: notServing, Workers: notServing,
+	})
+
+	c.checkAll(context.Background())
+	expect(t, c, map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"": serving, service: serving, MongoDB: serving, Workers: serving,
+	})
+
+	// One unhealthy component takes the server and its services down
+	mongoErr = errors.New("connection refused")
+	c.checkAll(context.Background())
+	expect(t, c, map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"": notServing, service: notServing, MongoDB: notServing, Workers: serving,
+	})
+
+	mongoErr = nil
+	c.checkAll(context.Background())
+	expect(t, c, map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"": serving, service: serving, MongoDB: serving,
+	})
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	c := New(Config{Timeout: 20 * time.Millisecond})
+	c.Add(Providers, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	started := time.Now()
+	c.checkAll(context.Background())
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("a hanging check took %s, want it cut at the timeout", elapsed)
+	}
+	expect(t, c, map[string]healthpb.HealthCheckResponse_ServingStatus{"": notServing, Providers: notServing})
+}
+
+func TestCheckerShutdown(t *testing.T) {
+	c := New(Config{})
+	c.Add(MongoDB, func(context.Context) error { return nil })
+	c.checkAll(context.Background())
+
+	c.Shutdown()
+	expect(t, c, map[string]healthpb.HealthCheckResponse_ServingStatus{"": notServing, MongoDB: notServing})
+
+	// Checks passing after the shutdown do not bring the server back
+	c.checkAll(context.Background())
+	expect(t, c, map[string]healthpb.HealthCheckResponse_ServingStatus{"": notServing, MongoDB: notServing})
+}
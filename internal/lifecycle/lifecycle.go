@@ -0,0 +1,125 @@
+package lifecycle
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// Config controls how long shutting down may take
+type Config struct {
+	GracePeriod  time.Duration // how long RPCs and executions may take to finish, 30s by default
+	CloseTimeout time.Duration // deadline of each close hook, 10s by default
+}
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Manager shuts the server down in order. Shutdown runs three phases:
+//
+//   - stop hooks, in order, announce the shutdown and stop taking work
+//   - drain hooks, concurrently, let the work in progress finish within
+//     the grace period, after which their context is done and they must
+//     cut it short
+//   - close hooks, in order, each with its own deadline, flush buffers and
+//     release resources once nothing uses them anymore
+type Manager struct {
+	cfg Config
+
+	mu      sync.Mutex
+	stop    []hook
+	drain   []hook
+	closers []hook
+	closed  bool
+}
+
+// New creates a Manager
+func New(cfg Config) *Manager {
+	if cfg.GracePeriod <= 0 {
+		cfg.GracePeriod = 30 * time.Second
+	}
+	if cfg.CloseTimeout <= 0 {
+		cfg.CloseTimeout = 10 * time.Second
+	}
+	return &Manager{cfg: cfg}
+}
+
+// OnStop registers a hook run first when shutting down
+func (m *Manager) OnStop(name string, fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stop = append(m.stop, hook{name, func(context.Context) error {
+		fn()
+		return nil
+	}})
+}
+
+// OnDrain registers a hook finishing work in progress within the grace
+// period
+func (m *Manager) OnDrain(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.drain = append(m.drain, hook{name, fn})
+}
+
+// OnClose registers a hook run once draining is over
+func (m *Manager) OnClose(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closers = append(m.closers, hook{name, fn})
+}
+
+// Wait blocks until one of signals is received and returns it
+func (m *Manager) Wait(signals ...os.Signal) os.Signal {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, signals...)
+	defer signal.Stop(sigChan)
+	return <-sigChan
+}
+
+// Shutdown runs the stop, drain and close hooks. Only the first call has
+// any effect.
+func (m *Manager) Shutdown() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	stop, drain, closers := m.stop, m.drain, m.closers
+	m.mu.Unlock()
+
+	for _, h := range stop {
+		h.fn(context.Background())
+	}
+
+	started := time.Now()
+	slog.Info("Draining", "grace_period", m.cfg.GracePeriod)
+	drainCtx, cancel := context.WithTimeout(context.Background(), m.cfg.GracePeriod)
+	var wg sync.WaitGroup
+	for _, h := range drain {
+		wg.Add(1)
+		go func(h hook) {
+			defer wg.Done()
+			if err := h.fn(drainCtx); err != nil {
+				slog.Warn("Drain cut short", "hook", h.name, "error", err)
+			}
+		}(h)
+	}
+	wg.Wait()
+	cancel()
+	slog.Info("Drained", "duration", time.Since(started))
+
+	for _, h := range closers {
+		ctx, cancel := context.WithTimeout(context.Background(), m.cfg.CloseTimeout)
+		if err := h.fn(ctx); err != nil {
+			slog.Error("Error closing", "hook", h.name, "error", err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,132 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects the hooks run, in the order they ran
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) record(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, name)
+}
+
+func (r *recorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func TestShutdownOrder(t *testing.T) {
+	m := New(Config{GracePeriod: time.Second})
+	var r recorder
+
+	// Registered out of phase order, run in phase order
+	m.OnClose("database", func(context.Context) error { r.record("close database"); return nil })
+	m.OnStop("health", func() { r.record("stop health") })
+	m.OnDrain("engine", func(context.Context) error { r.record("drain engine"); return nil })
+	m.OnClose("tracing", func(context.Context) error { r.record("close tracing"); return nil })
+	m.OnStop("scheduler", func() { r.record("stop scheduler") })
+
+	m.Shutdown()
+
+	want := []string{"stop health", "stop scheduler", "drain engine", "close database", "close tracing"}
+	if got := r.recorded(); !reflect.DeepEqual(got, want) {
+		t.Errorf("hooks ran %v, want %v", got, want)
+	}
+}
+
+func TestShutdownDrainsConcurrently(t *testing.T) {
+	m := New(Config{GracePeriod: time.Second})
+
+	// Each drain hook waits for the other to start, which only works when
+	// they run at the same time
+	a, b := make(chan struct{}), make(chan struct{})
+	wait := func(started, other chan struct{}) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			close(started)
+			select {
+			case <-other:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	var errs [2]error
+	m.OnDrain("grpc", func(ctx context.Context) error { errs[0] = wait(a, b)(ctx); return errs[0] })
+	m.OnDrain("engine", func(ctx context.Context) error { errs[1] = wait(b, a)(ctx); return errs[1] })
+
+	m.Shutdown()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("drain hook %d: %v, want both to finish within the grace period", i, err)
+		}
+	}
+}
+
+func TestShutdownGracePeriod(t *testing.T) {
+	const grace = 50 * time.Millisecond
+	m := New(Config{GracePeriod: grace, CloseTimeout: time.Second})
+	var r recorder
+
+	var drainErr error
+	m.OnDrain("engine", func(ctx context.Context) error {
+		// Work that outlasts the grace period is cut short at its end
+		<-ctx.Done()
+		drainErr = ctx.Err()
+		r.record("drain engine")
+		return drainErr
+	})
+	m.OnDrain("grpc", func(context.Context) error { r.record("drain grpc"); return nil })
+	var closeDeadline time.Duration
+	m.OnClose("database", func(ctx context.Context) error {
+		deadline, _ := ctx.Deadline()
+		closeDeadline = time.Until(deadline)
+		r.record("close database")
+		return errors.New("close failed")
+	})
+	m.OnClose("tracing", func(context.Context) error { r.record("close tracing"); return nil })
+
+	started := time.Now()
+	m.Shutdown()
+	elapsed := time.Since(started)
+
+	if !errors.Is(drainErr, context.DeadlineExceeded) {
+		t.Errorf("drain context error = %v, want the grace period exceeded", drainErr)
+	}
+	if elapsed < grace || elapsed > grace+time.Second/2 {
+		t.Errorf("Shutdown took %s, want about the %s grace period", elapsed, grace)
+	}
+	// Closing waits for every drain, and a failed close does not stop the
+	// next one
+	got := r.recorded()
+	if len(got) != 4 || got[2] != "close database" || got[3] != "close tracing" {
+		t.Errorf("hooks ran %v, want both drains, then close database and close tracing", got)
+	}
+	if closeDeadline <= grace || closeDeadline > time.Second {
+		t.Errorf("close hook had %s left, want its own deadline of up to 1s", closeDeadline)
+	}
+}
+
+func TestShutdownOnce(t *testing.T) {
+	m := New(Config{})
+	stops := 0
+	m.OnStop("health", func() { stops++ })
+
+	m.Shutdown()
+	m.Shutdown()
+	if stops != 1 {
+		t.Errorf("stop hook ran %d times, want once", stops)
+	}
+}
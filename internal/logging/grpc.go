@@ -0,0 +1,154 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// RequestIDHeader carries the request ID, taken from the caller when set
+// and returned in the response headers
+const RequestIDHeader = "x-request-id"
+
+// Principal identifies the caller of a request for the logs
+type Principal func(ctx context.Context) string
+
+// UnaryServerInterceptor logs every unary RPC with its request ID,
+// principal, method, duration and outcome. At debug level the request is
+// logged too, with redacted fields.
+func (l *Logger) UnaryServerInterceptor(principal Principal) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = l.requestContext(ctx, info.FullMethod, principal)
+		started := time.Now()
+		l.logRequest(ctx, req)
+
+		resp, err := handler(ctx, req)
+
+		// Most RPCs report failures in the response rather than as errors
+		var failure string
+		if r, ok := resp.(interface{ GetError() string }); ok && err == nil {
+			failure = r.GetError()
+		}
+		l.logDone(ctx, started, err, failure)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs every streaming RPC when it ends, like
+// UnaryServerInterceptor
+func (l *Logger) StreamServerInterceptor(principal Principal) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := l.requestContext(ss.Context(), info.FullMethod, principal)
+		started := time.Now()
+
+		err := handler(srv, &loggedStream{ServerStream: ss, ctx: ctx, logger: l})
+		l.logDone(ctx, started, err, "")
+		return err
+	}
+}
+
+// requestContext returns ctx carrying the request's log attributes, and
+// sends the request ID back to the caller
+func (l *Logger) requestContext(ctx context.Context, method string, principal Principal) context.Context {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIDHeader); len(ids) > 0 {
+			requestID = ids[0]
+		}
+	}
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, requestID))
+
+	attrs := []slog.Attr{
+		slog.String("request_id", requestID),
+		slog.String("method", method),
+	}
+	if principal != nil {
+		if p := principal(ctx); p != "" {
+			attrs = append(attrs, slog.String("principal", p))
+		}
+	}
+	return With(ctx, attrs...)
+}
+
+// logRequest logs the request at debug level, with redacted fields
+func (l *Logger) logRequest(ctx context.Context, req interface{}) {
+	if !slog.Default().Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	msg, ok := req.(protobuf.Message)
+	if !ok {
+		return
+	}
+	b, err := protojson.Marshal(msg)
+	if err != nil {
+		return
+	}
+	var fields interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return
+	}
+	slog.DebugContext(ctx, "RPC request", slog.Any("request", l.Redact(fields)))
+}
+
+func (l *Logger) logDone(ctx context.Context, started time.Time, err error, failure string) {
+	code := status.Code(err)
+	attrs := []interface{}{
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(started)),
+	}
+
+	switch {
+	case err != nil:
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+		level := slog.LevelWarn
+		switch code {
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			level = slog.LevelError
+		}
+		slog.Log(ctx, level, "RPC failed", attrs...)
+	case failure != "":
+		attrs = append(attrs, slog.String("error", failure))
+		slog.WarnContext(ctx, "RPC failed", attrs...)
+	default:
+		slog.InfoContext(ctx, "RPC handled", attrs...)
+	}
+}
+
+// loggedStream carries the request's log attributes in the stream context
+// and logs received messages at debug level
+type loggedStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	logger *Logger
+}
+
+func (s *loggedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *loggedStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.logger.logRequest(s.ctx, m)
+	}
+	return err
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,163 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Redacted replaces the values of redacted fields
+const Redacted = "[REDACTED]"
+
+// alwaysRedacted are fields holding credentials, redacted whatever the
+// configuration
+var alwaysRedacted = []string{"value", "authorization", "x-api-key"}
+
+// Config controls the format, level and redaction of logs
+type Config struct {
+	Level  string   // debug, info, warn or error; info by default
+	Format string   // FormatJSON or FormatText; JSON by default
+	Redact []string // fields whose values are replaced by Redacted, such as content fields
+}
+
+// Logger owns the process' slog handler: its level can be changed at
+// runtime, and it redacts the configured fields
+type Logger struct {
+	level  *slog.LevelVar
+	redact map[string]bool
+}
+
+// Setup creates the logger writing to out and makes it the default for
+// slog and the log package
+func Setup(cfg Config, out io.Writer) (*Logger, error) {
+	if out == nil {
+		out = os.Stderr
+	}
+	l := &Logger{
+		level:  new(slog.LevelVar),
+		redact: make(map[string]bool),
+	}
+	if cfg.Level != "" {
+		if err := l.SetLevel(cfg.Level); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range append(alwaysRedacted, cfg.Redact...) {
+		l.redact[strings.ToLower(field)] = true
+	}
+
+	opts := &slog.HandlerOptions{Level: l.level, ReplaceAttr: l.replaceAttr}
+	var handler slog.Handler
+	switch cfg.Format {
+	case FormatJSON, "":
+		handler = slog.NewJSONHandler(out, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(out, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q, must be %q or %q", cfg.Format, FormatJSON, FormatText)
+	}
+
+	slog.SetDefault(slog.New(contextHandler{handler}))
+	return l, nil
+}
+
+// Level returns the current level's name
+func (l *Logger) Level() string {
+	return strings.ToLower(l.level.Level().String())
+}
+
+// SetLevel changes the level of the logs written from now on
+func (l *Logger) SetLevel(name string) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("invalid log level %q, must be debug, info, warn or error", name)
+	}
+	l.level.Set(level)
+	return nil
+}
+
+// Redact returns v with the values of redacted fields replaced, walking
+// decoded JSON objects and arrays
+func (l *Logger) Redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			if l.redact[strings.ToLower(k)] {
+				redacted[k] = Redacted
+			} else {
+				redacted[k] = l.Redact(item)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = l.Redact(item)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+// replaceAttr redacts attributes named after redacted fields
+func (l *Logger) replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if l.redact[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
+
+type attrsKey struct{}
+
+// With returns ctx carrying attributes added to every record logged with
+// it, such as the request ID
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	combined := make([]slog.Attr, 0, len(existing)+len(attrs))
+	combined = append(combined, existing...)
+	combined = append(combined, attrs...)
+	return context.WithValue(ctx, attrsKey{}, combined)
+}
+
+// contextHandler adds the attributes of the record's context and its
+// trace ID
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// Fatal logs an error and exits
+func Fatal(msg string, args ...interface{}) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "mcp"
+
+// Metrics collects the server's Prometheus metrics
+type Metrics struct {
+	registry *prometheus.Registry
+
+	rpcRequests      *prometheus.CounterVec
+	rpcDuration      *prometheus.HistogramVec
+	mongoDuration    *prometheus.HistogramVec
+	providerDuration *prometheus.HistogramVec
+	providerTokens   *prometheus.CounterVec
+}
+
+// New creates the metrics and registers them, along with the Go runtime
+// and process metrics
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		rpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "gRPC requests handled, by method and status code.",
+		}, []string{"method", "code"}),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Time taken to handle gRPC requests, by method. Streams are timed until they end.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		mongoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "mongodb_command_duration_seconds",
+			Help:      "Time taken by MongoDB commands, by command and outcome.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"command", "outcome"}),
+		providerDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "provider_request_duration_seconds",
+			Help:      "Time taken by model provider calls, by provider, model and outcome.",
+			Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		}, []string{"provider", "model", "outcome"}),
+		providerTokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "provider_tokens_total",
+			Help:      "Tokens sent to and generated by model providers, by provider, model and kind (prompt or completion).",
+		}, []string{"provider", "model", "kind"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.rpcRequests,
+		m.rpcDuration,
+		m.mongoDuration,
+		m.providerDuration,
+		m.providerTokens,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Serve serves the metrics on addr (":9090" by default) under path
+// ("/metrics" by default) in the background. The returned function stops
+// serving once pending scrapes are answered.
+func (m *Metrics) Serve(addr, path string) func(context.Context) error {
+	if addr == "" {
+		addr = ":9090"
+	}
+	if path == "" {
+		path = "/metrics"
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, m.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		slog.Info("Serving metrics", "address", addr, "path", path)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("Metrics server failed", "error", err)
+		}
+	}()
+	return srv.Shutdown
+}
+
+// UnaryServerInterceptor counts and times unary RPCs
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		started := time.Now()
+		resp, err := handler(ctx, req)
+		m.observeRPC(info.FullMethod, started, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor counts and times streaming RPCs
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		started := time.Now()
+		err := handler(srv, ss)
+		m.observeRPC(info.FullMethod, started, err)
+		return err
+	}
+}
+
+func (m *Metrics) observeRPC(method string, started time.Time, err error) {
+	m.rpcRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+	m.rpcDuration.WithLabelValues(method).Observe(time.Since(started).Seconds())
+}
+
+// CommandMonitor times the commands sent to MongoDB. Pass it to the
+// client options with SetMonitor.
+func (m *Metrics) CommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			m.mongoDuration.WithLabelValues(e.CommandName, "success").Observe(e.Duration.Seconds())
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			m.mongoDuration.WithLabelValues(e.CommandName, "failure").Observe(e.Duration.Seconds())
+		},
+	}
+}
+
+// ObserveProvider records a call to a model provider and the tokens it
+// used. It does nothing on a nil Metrics, so the engine need not check
+// whether metrics are enabled.
+func (m *Metrics) ObserveProvider(provider, model string, took time.Duration, promptTokens, completionTokens int, err error) {
+	if m == nil {
+		return
+	}
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	m.providerDuration.WithLabelValues(provider, model, outcome).Observe(took.Seconds())
+	m.providerTokens.WithLabelValues(provider, model, "prompt").Add(float64(promptTokens))
+	m.providerTokens.WithLabelValues(provider, model, "completion").Add(float64(completionTokens))
+}
+
+// RegisterWorkers reports the utilisation of the execution workers, read
+// from workers at every scrape
+func (m *Metrics) RegisterWorkers(workers func() (busy, total int)) {
+	m.registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "workers_busy",
+			Help:      "Execution workers of this server running an execution.",
+		}, func() float64 {
+			busy, _ := workers()
+			return float64(busy)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "workers",
+			Help:      "Execution workers of this server.",
+		}, func() float64 {
+			_, total := workers()
+			return float64(total)
+		}),
+	)
+}
+
+// RegisterQueue reports the depth of the execution queue, shared by every
+// server, read from depths at every scrape
+func (m *Metrics) RegisterQueue(depths func(ctx context.Context) ([]protocol.QueueDepth, error)) {
+	m.registry.MustRegister(&queueCollector{depths: depths})
+}
+
+var queueDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "queue_executions"),
+	"Executions waiting for (pending) or holding (running) a worker, by priority and tenant.",
+	[]string{"priority", "tenant", "status"}, nil,
+)
+
+// queueCollector queries the queue depths when scraped
+type queueCollector struct {
+	depths func(ctx context.Context) ([]protocol.QueueDepth, error)
+}
+
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDesc
+}
+
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	depths, err := c.depths(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(queueDesc, err)
+		return
+	}
+	for _, d := range depths {
+		ch <- prometheus.MustNewConstMetric(queueDesc, prometheus.GaugeValue, float64(d.Pending), d.Priority, d.Tenant, "pending")
+		ch <- prometheus.MustNewConstMetric(queueDesc, prometheus.GaugeValue, float64(d.Running), d.Priority, d.Tenant, "running")
+	}
+}
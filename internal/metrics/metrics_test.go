@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/protocol"
+	"go.mongodb.org/mongo-driver/event"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scrape returns the metrics as Prometheus serves them, and the status of
+// the scrape
+func scrape(t *testing.T, m *Metrics) (string, int) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body), rec.Code
+}
+
+// expectLines checks that the scraped metrics contain every line of want
+func expectLines(t *testing.T, m *Metrics, want ...string) {
+	t.Helper()
+	body, code := scrape(t, m)
+	if code != http.StatusOK {
+		t.Fatalf("scrape returned %d:\n%s", code, body)
+	}
+	lines := make(map[string]bool)
+	for _, line := range strings.Split(body, "\n") {
+		lines[line] = true
+	}
+	for _, w := range want {
+		if !lines[w] {
+			t.Errorf("metrics lack %q", w)
+		}
+	}
+}
+
+func TestRPCMetrics(t *testing.T) {
+	m := New()
+	unary := m.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.MCPService/GetModel"}
+
+	ok := func(ctx context.Context, req interface{}) (interface{}, error) { return "model", nil }
+	notFound := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "model not found")
+	}
+	for _, handler := range []grpc.UnaryHandler{ok, ok, notFound} {
+		unary(context.Background(), nil, info, handler)
+	}
+
+	stream := m.StreamServerInterceptor()
+	stream(nil, nil, &grpc.StreamServerInfo{FullMethod: "/proto.MCPService/Subscribe"}, func(interface{}, grpc.ServerStream) error {
+		return nil
+	})
+
+	expectLines(t, m,
+		`mcp_grpc_requests_total{code="OK",method="/proto.MCPService/GetModel"} 2`,
+		`mcp_grpc_requests_total{code="NotFound",method="/proto.MCPService/GetModel"} 1`,
+		`mcp_grpc_requests_total{code="OK",method="/proto.MCPService/Subscribe"} 1`,
+		`mcp_grpc_request_duration_seconds_count{method="/proto.MCPService/GetModel"} 3`,
+	)
+}
+
+func TestMongoDBMetrics(t *testing.T) {
+	m := New()
+	monitor := m.CommandMonitor()
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: 2 * time.Millisecond},
+	})
+	monitor.Failed(context.Background(), &event.CommandFailedEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "insert", Duration: time.Second},
+	})
+
+	expectLines(t, m,
+		`mcp_mongodb_command_duration_seconds_count{command="find",outcome="success"} 1`,
+		`mcp_mongodb_command_duration_seconds_bucket{command="find",outcome="success",le="0.0025"} 1`,
+		`mcp_mongodb_command_duration_seconds_count{command="insert",outcome="failure"} 1`,
+	)
+}
+
+func TestProviderMetrics(t *testing.T) {
+	m := New()
+	m.ObserveProvider("openai", "gpt-4", 2*time.Second, 100, 20, nil)
+	m.ObserveProvider("openai", "gpt-4", time.Second, 50, 0, errors.New("rate limited"))
+
+	expectLines(t, m,
+		`mcp_provider_tokens_total{kind="prompt",model="gpt-4",provider="openai"} 150`,
+		`mcp_provider_tokens_total{kind="completion",model="gpt-4",provider="openai"} 20`,
+		`mcp_provider_request_duration_seconds_count{model="gpt-4",outcome="success",provider="openai"} 1`,
+		`mcp_provider_request_duration_seconds_count{model="gpt-4",outcome="failure",provider="openai"} 1`,
+	)
+
+	// Metrics are optional in the engine
+	var disabled *Metrics
+	disabled.ObserveProvider("openai", "gpt-4", time.Second, 1, 1, nil)
+}
+
+func TestWorkerAndQueueMetrics(t *testing.T) {
+	m := New()
+	busy := 3
+	m.RegisterWorkers(func() (int, int) { return busy, 10 })
+	var queueErr error
+	m.RegisterQueue(func(ctx context.Context) ([]protocol.QueueDepth, error) {
+		return []protocol.QueueDepth{
+			{QueueClass: protocol.QueueClass{Priority: protocol.PriorityInteractive, Tenant: "acme"}, Pending: 4, Running: 2},
+		}, queueErr
+	})
+
+	expectLines(t, m,
+		`mcp_workers_busy 3`,
+		`mcp_workers 10`,
+		`mcp_queue_executions{priority="interactive",status="pending",tenant="acme"} 4`,
+		`mcp_queue_executions{priority="interactive",status="running",tenant="acme"} 2`,
+	)
+
+	// Gauges are read at every scrape
+	busy = 7
+	expectLines(t, m, `mcp_workers_busy 7`)
+
+	queueErr = errors.New("database unavailable")
+	if body, code := scrape(t, m); code != http.StatusInternalServerError {
+		t.Errorf("scrape with the queue unavailable returned %d, want 500:\n%s", code, body)
+	}
+}
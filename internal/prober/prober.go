@@ -0,0 +1,234 @@
+package prober
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/provider"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/health"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/lease"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+)
+
+// leaderLease names the lease held by the server that probes models
+const leaderLease = "prober"
+
+// probePrompt is the cheap request sent to every model
+const probePrompt = "ping"
+
+// Config controls how models are probed
+type Config struct {
+	Owner            string        // identifies this server in the leader lease
+	Interval         time.Duration // how often models are probed
+	Timeout          time.Duration // how long a probe may take
+	FailureThreshold int           // failed probes in a row after which a model is unhealthy
+	Concurrency      int           // how many models are probed at once, 10 by default
+}
+
+// Prober periodically sends a cheap request to every model's provider and
+// records its latency and availability. Only the holder of the leader
+// lease probes, so providers see one probe per interval however many
+// servers run; every server reloads the statuses it records. The statuses
+// only steer routers: executions naming an unhealthy model directly still
+// call it.
+type Prober struct {
+	cfg       Config
+	models    *model.ModelRepository
+	health    *health.HealthRepository
+	leases    *lease.LeaseRepository
+	providers *provider.Registry
+
+	mu        sync.RWMutex
+	unhealthy map[string]bool
+	running   sync.WaitGroup
+}
+
+// New creates a new Prober
+func New(cfg Config, models *model.ModelRepository, health *health.HealthRepository, leases *lease.LeaseRepository, providers *provider.Registry) *Prober {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 2
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 10
+	}
+
+	return &Prober{
+		cfg:       cfg,
+		models:    models,
+		health:    health,
+		leases:    leases,
+		providers: providers,
+		unhealthy: make(map[string]bool),
+	}
+}
+
+// Healthy reports whether a model may be routed to. Models never probed
+// count as healthy.
+func (p *Prober) Healthy(modelID string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return !p.unhealthy[modelID]
+}
+
+// Start probes models until ctx is done, then gives up leadership
+func (p *Prober) Start(ctx context.Context) {
+	p.running.Add(1)
+	go func() {
+		defer p.running.Done()
+		ticker := time.NewTicker(p.cfg.Interval)
+		defer ticker.Stop()
+
+		leader := false
+		for {
+			leader = p.tick(ctx)
+			select {
+			case <-ctx.Done():
+				if leader {
+					releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					if err := p.leases.Release(releaseCtx, leaderLease, p.cfg.Owner); err != nil {
+						slog.Error("Error releasing prober leadership", "error", err)
+					}
+					cancel()
+				}
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait blocks until the prober has stopped, after its context is done, and
+// released its leadership
+func (p *Prober) Wait() {
+	p.running.Wait()
+}
+
+// tick probes every model while this server is the leader, then reloads
+// the statuses. It returns whether this server is the leader.
+func (p *Prober) tick(ctx context.Context) bool {
+	// Leadership lasts a few intervals, so a stopped leader is replaced
+	// without waiting long
+	leader, err := p.leases.Acquire(ctx, leaderLease, p.cfg.Owner, 3*p.cfg.Interval)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Error acquiring prober leadership", "error", err)
+		}
+		return false
+	}
+	if leader {
+		p.probeAll(ctx)
+	}
+
+	statuses, err := p.health.List(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Error loading model health", "error", err)
+		}
+		return leader
+	}
+	unhealthy := make(map[string]bool)
+	for _, s := range statuses {
+		if s.Status == health.StatusUnhealthy {
+			unhealthy[s.ModelID] = true
+		}
+	}
+	p.mu.Lock()
+	p.unhealthy = unhealthy
+	p.mu.Unlock()
+	return leader
+}
+
+// probeAll probes every model, up to the configured number at once.
+// Routers are not probed themselves, nor are models whose "health_check"
+// parameter is "false".
+func (p *Prober) probeAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, p.cfg.Concurrency)
+	pageToken := ""
+	for {
+		models, next, err := p.models.List(ctx, 100, pageToken)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("Error listing models to probe", "error", err)
+			}
+			break
+		}
+		for _, m := range models {
+			if m.IsRouter() || m.Parameters["health_check"] == "false" {
+				continue
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			}
+			wg.Add(1)
+			go func(m *model.Model) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				p.probe(ctx, m)
+			}(m)
+		}
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+	wg.Wait()
+}
+
+// probe sends the probe request to a model and records the outcome
+func (p *Prober) probe(ctx context.Context, m *model.Model) {
+	check := &health.Check{
+		ModelID:   m.ID.Hex(),
+		CheckedAt: time.Now(),
+	}
+
+	prov, err := p.providers.ForModel(m)
+	var apiKey string
+	if err == nil {
+		apiKey, err = p.providers.APIKey(ctx, m)
+	}
+	if err == nil {
+		params := make(map[string]string, len(m.Parameters)+1)
+		for k, v := range m.Parameters {
+			params[k] = v
+		}
+		params["max_tokens"] = "1"
+
+		probeCtx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+		_, err = prov.Complete(probeCtx, &provider.Request{
+			Model:      m,
+			Prompt:     probePrompt,
+			Parameters: params,
+			APIKey:     apiKey,
+		})
+		cancel()
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	check.LatencyMs = time.Since(check.CheckedAt).Milliseconds()
+	check.OK = err == nil
+	if err != nil {
+		check.Error = err.Error()
+	}
+
+	status, err := p.health.Record(ctx, check, p.cfg.FailureThreshold)
+	if err != nil {
+		slog.Error("Error recording model health", "model_id", m.ID.Hex(), "error", err)
+		return
+	}
+	if status.Status == health.StatusUnhealthy && status.ConsecutiveFailures == p.cfg.FailureThreshold {
+		slog.Warn("Model is unhealthy", "model_id", m.ID.Hex(), "model", m.Name, "error", check.Error)
+	}
+}
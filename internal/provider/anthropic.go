@@ -0,0 +1,138 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Anthropic calls the Anthropic messages API. The API key is taken from
+// the secret named by the "api_key_secret" model parameter, or else the
+// environment variable named by "api_key_env" (ANTHROPIC_API_KEY by
+// default).
+type Anthropic struct {
+	client *http.Client
+}
+
+// NewAnthropic creates an Anthropic provider
+func NewAnthropic(client *http.Client) *Anthropic {
+	return &Anthropic{client: client}
+}
+
+// Name implements the Provider interface
+func (p *Anthropic) Name() string {
+	return "anthropic"
+}
+
+type anthropicRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	MaxTokens   int             `json:"max_tokens"`
+	Stream      bool            `json:"stream,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage anthropicUsage `json:"usage"`
+}
+
+type anthropicEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Usage anthropicUsage `json:"usage"`
+}
+
+// Complete implements the Provider interface
+func (p *Anthropic) Complete(ctx context.Context, req *Request) (*Response, error) {
+	body, url, headers := p.request(req)
+
+	var resp anthropicResponse
+	if err := postJSON(ctx, p.client, p.Name(), url, headers, body, &resp); err != nil {
+		return nil, err
+	}
+
+	var output strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			output.WriteString(block.Text)
+		}
+	}
+
+	return &Response{
+		Output:           output.String(),
+		PromptTokens:     resp.Usage.InputTokens,
+		CompletionTokens: resp.Usage.OutputTokens,
+	}, nil
+}
+
+// Stream implements the Streamer interface
+func (p *Anthropic) Stream(ctx context.Context, req *Request, onDelta func(delta string)) (*Response, error) {
+	body, url, headers := p.request(req)
+	body.Stream = true
+
+	var output strings.Builder
+	resp := &Response{}
+	err := postSSE(ctx, p.client, p.Name(), url, headers, body, func(event, data string) error {
+		var ev anthropicEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return err
+		}
+
+		switch ev.Type {
+		case "message_start":
+			resp.PromptTokens = ev.Message.Usage.InputTokens
+		case "content_block_delta":
+			if ev.Delta.Type == "text_delta" {
+				output.WriteString(ev.Delta.Text)
+				onDelta(ev.Delta.Text)
+			}
+		case "message_delta":
+			resp.CompletionTokens = ev.Usage.OutputTokens
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Output = output.String()
+	return resp, nil
+}
+
+// request builds the messages request body, URL and headers
+func (p *Anthropic) request(req *Request) (*anthropicRequest, string, map[string]string) {
+	body := &anthropicRequest{
+		Model:     ModelName(req.Model),
+		Messages:  []openAIMessage{{Role: "user", Content: req.Prompt}},
+		MaxTokens: intParam(req.Parameters, "max_tokens", 1024),
+	}
+	if temperature, ok := floatParam(req.Parameters, "temperature"); ok {
+		body.Temperature = &temperature
+	}
+
+	baseURL := req.Parameters["base_url"]
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com/v1"
+	}
+
+	return body, strings.TrimSuffix(baseURL, "/") + "/messages", map[string]string{
+		"x-api-key":         apiKey(req, "ANTHROPIC_API_KEY"),
+		"anthropic-version": "2023-06-01",
+	}
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"context"
+	"strings"
+)
+
+// Echo answers every request with its prompt. It needs no backend, which
+// makes it useful for trying out protocols locally.
+type Echo struct{}
+
+// NewEcho creates an Echo provider
+func NewEcho() *Echo {
+	return &Echo{}
+}
+
+// Name implements the Provider interface
+func (p *Echo) Name() string {
+	return "echo"
+}
+
+// Complete implements the Provider interface
+func (p *Echo) Complete(ctx context.Context, req *Request) (*Response, error) {
+	return &Response{Output: req.Prompt}, nil
+}
+
+// Stream implements the Streamer interface, sending the prompt word by word
+func (p *Echo) Stream(ctx context.Context, req *Request, onDelta func(delta string)) (*Response, error) {
+	for _, word := range strings.SplitAfter(req.Prompt, " ") {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		onDelta(word)
+	}
+	return &Response{Output: req.Prompt}, nil
+}
@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAI calls OpenAI compatible chat completion APIs. The "base_url" model
+// parameter points it at other compatible servers. The API key is taken
+// from the secret named by "api_key_secret", or else the environment
+// variable named by "api_key_env" (OPENAI_API_KEY by default).
+type OpenAI struct {
+	client *http.Client
+}
+
+// NewOpenAI creates an OpenAI provider
+func NewOpenAI(client *http.Client) *OpenAI {
+	return &OpenAI{client: client}
+}
+
+// Name implements the Provider interface
+func (p *OpenAI) Name() string {
+	return "openai"
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Stream      bool            `json:"stream,omitempty"`
+	// StreamOptions asks for token usage in the last streamed chunk
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage openAIUsage `json:"usage"`
+}
+
+type openAIChunk struct {
+	Choices []struct {
+		Delta openAIMessage `json:"delta"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+// Complete implements the Provider interface
+func (p *OpenAI) Complete(ctx context.Context, req *Request) (*Response, error) {
+	body, url, headers := p.request(req)
+
+	var resp openAIResponse
+	if err := postJSON(ctx, p.client, p.Name(), url, headers, body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("openai returned no choices")
+	}
+
+	return &Response{
+		Output:           resp.Choices[0].Message.Content,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+	}, nil
+}
+
+// Stream implements the Streamer interface
+func (p *OpenAI) Stream(ctx context.Context, req *Request, onDelta func(delta string)) (*Response, error) {
+	body, url, headers := p.request(req)
+	body.Stream = true
+	body.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+
+	var output strings.Builder
+	resp := &Response{}
+	err := postSSE(ctx, p.client, p.Name(), url, headers, body, func(event, data string) error {
+		if data == "[DONE]" {
+			return nil
+		}
+
+		var chunk openAIChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return err
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				output.WriteString(choice.Delta.Content)
+				onDelta(choice.Delta.Content)
+			}
+		}
+		if chunk.Usage != nil {
+			resp.PromptTokens = chunk.Usage.PromptTokens
+			resp.CompletionTokens = chunk.Usage.CompletionTokens
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Output = output.String()
+	return resp, nil
+}
+
+// request builds the chat completion request body, URL and headers
+func (p *OpenAI) request(req *Request) (*openAIRequest, string, map[string]string) {
+	body := &openAIRequest{
+		Model:     ModelName(req.Model),
+		Messages:  []openAIMessage{{Role: "user", Content: req.Prompt}},
+		MaxTokens: intParam(req.Parameters, "max_tokens", 0),
+	}
+	if temperature, ok := floatParam(req.Parameters, "temperature"); ok {
+		body.Temperature = &temperature
+	}
+
+	baseURL := req.Parameters["base_url"]
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+
+	return body, strings.TrimSuffix(baseURL, "/") + "/chat/completions", map[string]string{
+		"Authorization": "Bearer " + apiKey(req, "OPENAI_API_KEY"),
+	}
+}
+
+// postJSON sends body as JSON and decodes the JSON answer into out
+func postJSON(ctx context.Context, client *http.Client, provider, url string, headers map[string]string, body, out interface{}) error {
+	httpResp, err := post(ctx, client, provider, url, headers, body)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// postSSE sends body as JSON and calls onData for each server-sent event
+// of the answer
+func postSSE(ctx context.Context, client *http.Client, provider, url string, headers map[string]string, body interface{}, onData func(event, data string) error) error {
+	httpResp, err := post(ctx, client, provider, url, headers, body)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	var event string
+	scanner := bufio.NewScanner(httpResp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if err := onData(event, strings.TrimSpace(strings.TrimPrefix(line, "data:"))); err != nil {
+				return err
+			}
+		case line == "":
+			event = ""
+		}
+	}
+	return scanner.Err()
+}
+
+// post sends body as JSON, turning non-2xx answers into a StatusError
+func post(ctx context.Context, client *http.Client, provider, url string, headers map[string]string, body interface{}) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode/100 != 2 {
+		defer httpResp.Body.Close()
+		data, _ := io.ReadAll(httpResp.Body)
+		return nil, &StatusError{Provider: provider, Code: httpResp.StatusCode, Body: string(data)}
+	}
+	return httpResp, nil
+}
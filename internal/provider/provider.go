@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Request is a single call to a model
+type Request struct {
+	Model      *model.Model
+	Type       string
+	Prompt     string
+	Parameters map[string]string
+	// APIKey is the credential revealed from the model's secret. Providers
+	// fall back to their API key environment variable when it is empty.
+	APIKey string
+}
+
+// Response is the output of a model call
+type Response struct {
+	Output           string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Provider calls the backend serving a model
+type Provider interface {
+	Name() string
+	Complete(ctx context.Context, req *Request) (*Response, error)
+}
+
+// Streamer is implemented by providers that can stream their output.
+// Stream calls onDelta with each chunk of output as it arrives and returns
+// the complete response at the end.
+type Streamer interface {
+	Stream(ctx context.Context, req *Request, onDelta func(delta string)) (*Response, error)
+}
+
+// StatusError is returned when a provider answers with a non-2xx status
+type StatusError struct {
+	Provider string
+	Code     int
+	Body     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned %d: %s", e.Provider, e.Code, e.Body)
+}
+
+// Retryable reports whether a failed call is worth retrying: rate limits,
+// server errors and transport failures are, while other client errors and
+// cancellations are not
+func Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code == http.StatusTooManyRequests || statusErr.Code >= 500
+	}
+	return true
+}
+
+// Secrets reveals the values of named secrets. Reveal fails unless the
+// secret may be sent to host, the provider's own endpoint when empty.
+type Secrets interface {
+	Reveal(ctx context.Context, name, host string) (string, error)
+}
+
+// Registry maps provider names to providers
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+	secrets   Secrets
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]Provider),
+	}
+}
+
+// DefaultRegistry creates a Registry with the built-in providers
+func DefaultRegistry() *Registry {
+	client := &http.Client{
+		Timeout:   5 * time.Minute,
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+
+	r := NewRegistry()
+	r.Register(NewOpenAI(client))
+	r.Register(NewAnthropic(client))
+	r.Register(NewEcho())
+	return r
+}
+
+// Register adds a provider to the registry
+func (r *Registry) Register(p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[p.Name()] = p
+}
+
+// SetSecrets sets where the API keys of models referencing a secret come
+// from
+func (r *Registry) SetSecrets(secrets Secrets) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.secrets = secrets
+}
+
+// APIKey reveals the secret named by the model's "api_key_secret"
+// parameter, provided the secret may be sent to the host of the model's
+// "base_url". It returns an empty key when the model references none.
+func (r *Registry) APIKey(ctx context.Context, m *model.Model) (string, error) {
+	name := m.Parameters["api_key_secret"]
+	if name == "" {
+		return "", nil
+	}
+	host, err := BaseURLHost(m.Parameters)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.RLock()
+	secrets := r.secrets
+	r.mu.RUnlock()
+	if secrets == nil {
+		return "", fmt.Errorf("model %s references secret %s but secrets are not configured", m.Name, name)
+	}
+	return secrets.Reveal(ctx, name, host)
+}
+
+// BaseURLHost returns the host name of the "base_url" parameter, or an
+// empty string when it is not set and providers call their own endpoint
+func BaseURLHost(params map[string]string) (string, error) {
+	baseURL := params["base_url"]
+	if baseURL == "" {
+		return "", nil
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid base_url %q", baseURL)
+	}
+	return strings.ToLower(u.Hostname()), nil
+}
+
+// apiKey returns the request's API key, or else the value of the
+// environment variable named by the "api_key_env" parameter or defaultEnv.
+// Only the variables in model.APIKeyEnvs are read.
+func apiKey(req *Request, defaultEnv string) string {
+	if req.APIKey != "" {
+		return req.APIKey
+	}
+	keyEnv := req.Parameters["api_key_env"]
+	if keyEnv == "" {
+		keyEnv = defaultEnv
+	}
+	for _, allowed := range model.APIKeyEnvs {
+		if keyEnv == allowed {
+			return os.Getenv(keyEnv)
+		}
+	}
+	return ""
+}
+
+// ForModel returns the provider named by the model's "provider" parameter,
+// or the one inferred from the backend model name
+func (r *Registry) ForModel(m *model.Model) (Provider, error) {
+	name := m.Parameters["provider"]
+	if name == "" {
+		name = inferProvider(ModelName(m))
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("no provider for model %s (type %s)", m.Name, m.Type)
+	}
+	return p, nil
+}
+
+// ModelName returns the name the backend knows the model by: the "model"
+// parameter, or else the model type
+func ModelName(m *model.Model) string {
+	if name := m.Parameters["model"]; name != "" {
+		return name
+	}
+	return m.Type
+}
+
+func inferProvider(modelName string) string {
+	switch {
+	case strings.HasPrefix(modelName, "gpt-"), strings.HasPrefix(modelName, "o1"):
+		return "openai"
+	case strings.HasPrefix(modelName, "claude"):
+		return "anthropic"
+	default:
+		return modelName
+	}
+}
+
+// floatParam parses a numeric request parameter, reporting whether it was set
+func floatParam(params map[string]string, key string) (float64, bool) {
+	v, err := strconv.ParseFloat(params[key], 64)
+	return v, err == nil
+}
+
+// intParam parses an integer request parameter, falling back to def
+func intParam(params map[string]string, key string, def int) int {
+	v, err := strconv.Atoi(params[key])
+	if err != nil {
+		return def
+	}
+	return v
+}
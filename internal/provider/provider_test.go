@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/model"
+)
+
+func TestAPIKey(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "openai-key")
+	t.Setenv("ANTHROPIC_API_KEY", "anthropic-key")
+	t.Setenv("MCP_SECRETS_KEY", "master-key")
+
+	tests := []struct {
+		name string
+		req  *Request
+		want string
+	}{
+		{"default variable", &Request{}, "openai-key"},
+		{"allowed variable", &Request{Parameters: map[string]string{"api_key_env": "ANTHROPIC_API_KEY"}}, "anthropic-key"},
+		{"other variable", &Request{Parameters: map[string]string{"api_key_env": "MCP_SECRETS_KEY"}}, ""},
+		{"secret first", &Request{APIKey: "secret-key", Parameters: map[string]string{"api_key_env": "ANTHROPIC_API_KEY"}}, "secret-key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apiKey(tt.req, "OPENAI_API_KEY"); got != tt.want {
+				t.Errorf("apiKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeSecrets reveals every secret to the hosts it allows
+type fakeSecrets struct {
+	hosts map[string]bool
+}
+
+func (s fakeSecrets) Reveal(ctx context.Context, name, host string) (string, error) {
+	if host != "" && !s.hosts[host] {
+		return "", fmt.Errorf("secret %s may not be sent to %s", name, host)
+	}
+	return "value of " + name, nil
+}
+
+func TestRegistryAPIKey(t *testing.T) {
+	r := NewRegistry()
+	r.SetSecrets(fakeSecrets{hosts: map[string]bool{"llm.internal": true}})
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		want    string
+		wantErr string
+	}{
+		{"no secret", map[string]string{"base_url": "https://attacker.example"}, "", ""},
+		{"provider endpoint", map[string]string{"api_key_secret": "prod"}, "value of prod", ""},
+		{"allowed host", map[string]string{"api_key_secret": "prod", "base_url": "https://LLM.internal:8443/v1"}, "value of prod", ""},
+		{"other host", map[string]string{"api_key_secret": "prod", "base_url": "https://attacker.example/v1"}, "", "secret prod may not be sent to attacker.example"},
+		{"invalid base_url", map[string]string{"api_key_secret": "prod", "base_url": "/v1"}, "", `invalid base_url "/v1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.APIKey(context.Background(), &model.Model{Name: "m", Parameters: tt.params})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("APIKey error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("APIKey: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("APIKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/lease"
+	"github.com/DavutcanJ/mongo-mcp-server/internal/service/schedule"
+)
+
+// leaderLease names the lease held by the server that fires schedules
+const leaderLease = "scheduler"
+
+// SubmitFunc starts the execution a schedule asks for and returns its ID
+type SubmitFunc func(ctx context.Context, req schedule.Request) (string, error)
+
+// Config controls how often schedules are checked
+type Config struct {
+	Owner    string        // identifies this server in the leader lease
+	Interval time.Duration // how often due schedules are looked for
+	LeaseTTL time.Duration // how long leadership lasts without renewal
+}
+
+// Scheduler fires due schedules. Every server runs one, but only the
+// holder of the leader lease fires schedules; the others take over when
+// its lease expires. Each run also advances the schedule with a
+// compare-and-set on its next run time, so a run never fires twice.
+type Scheduler struct {
+	cfg       Config
+	schedules *schedule.ScheduleRepository
+	leases    *lease.LeaseRepository
+	submit    SubmitFunc
+	running   sync.WaitGroup
+}
+
+// New creates a new Scheduler
+func New(cfg Config, schedules *schedule.ScheduleRepository, leases *lease.LeaseRepository, submit SubmitFunc) *Scheduler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	if cfg.LeaseTTL <= cfg.Interval {
+		cfg.LeaseTTL = 3 * cfg.Interval
+	}
+
+	return &Scheduler{
+		cfg:       cfg,
+		schedules: schedules,
+		leases:    leases,
+		submit:    submit,
+	}
+}
+
+// Start fires due schedules until ctx is done, then gives up leadership
+func (s *Scheduler) Start(ctx context.Context) {
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+
+		leader := false
+		for {
+			leader = s.tick(ctx, leader)
+			select {
+			case <-ctx.Done():
+				if leader {
+					releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					if err := s.leases.Release(releaseCtx, leaderLease, s.cfg.Owner); err != nil {
+						slog.Error("Error releasing scheduler leadership", "error", err)
+					}
+					cancel()
+				}
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Wait blocks until the scheduler has stopped, after its context is done,
+// and released its leadership
+func (s *Scheduler) Wait() {
+	s.running.Wait()
+}
+
+// tick renews leadership and, while leader, fires the due schedules. It
+// returns whether this server is the leader.
+func (s *Scheduler) tick(ctx context.Context, wasLeader bool) bool {
+	leader, err := s.leases.Acquire(ctx, leaderLease, s.cfg.Owner, s.cfg.LeaseTTL)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("Error acquiring scheduler leadership", "error", err)
+		}
+		return false
+	}
+	if leader != wasLeader {
+		if leader {
+			slog.Info("Scheduler leadership acquired", "owner", s.cfg.Owner)
+		} else {
+			slog.Info("Scheduler leadership lost", "owner", s.cfg.Owner)
+		}
+	}
+	if !leader {
+		return false
+	}
+
+	now := time.Now()
+	due, err := s.schedules.Due(ctx, now)
+	if err != nil {
+		slog.Error("Error loading due schedules", "error", err)
+		return true
+	}
+	for _, sch := range due {
+		s.fire(ctx, sch, now)
+	}
+	return true
+}
+
+// fire starts one run of a due schedule. Runs missed while no server was
+// leader are not caught up: the schedule next runs at its first run time
+// from now.
+func (s *Scheduler) fire(ctx context.Context, sch *schedule.Schedule, now time.Time) {
+	next, err := sch.Next(now)
+	if err != nil {
+		slog.Error("Error computing next run of schedule", "schedule_id", sch.ID.Hex(), "error", err)
+		return
+	}
+
+	ok, err := s.schedules.Advance(ctx, sch, now, next)
+	if err != nil {
+		slog.Error("Error advancing schedule", "schedule_id", sch.ID.Hex(), "error", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	executionID, err := s.submit(ctx, sch.Request)
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+		slog.Warn("Schedule failed to start", "schedule_id", sch.ID.Hex(), "schedule", sch.Name, "error", err)
+	} else {
+		slog.Info("Schedule started execution", "schedule_id", sch.ID.Hex(), "schedule", sch.Name, "execution_id", executionID)
+	}
+	if err := s.schedules.RecordRun(ctx, sch.ID, executionID, errMsg); err != nil {
+		slog.Error("Error recording run of schedule", "schedule_id", sch.ID.Hex(), "error", err)
+	}
+}
@@ -97,3 +97,71 @@ func (r *ContextRepository) List(ctx context.Context, pageSize int32, pageToken
 
 	return contexts, nextPageToken, nil
 }
+
+// HasModel reports whether the context is linked to the given model
+func (c *Context) HasModel(modelID string) bool {
+	for _, id := range c.ModelIDs {
+		if id == modelID {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkModel links a model to a context
+func (r *ContextRepository) LinkModel(ctx context.Context, id, modelID string) (*Context, error) {
+	return r.updateModelIDs(ctx, id, bson.M{"$addToSet": bson.M{"model_ids": modelID}})
+}
+
+// UnlinkModel removes the link between a model and a context
+func (r *ContextRepository) UnlinkModel(ctx context.Context, id, modelID string) (*Context, error) {
+	return r.updateModelIDs(ctx, id, bson.M{"$pull": bson.M{"model_ids": modelID}})
+}
+
+func (r *ContextRepository) updateModelIDs(ctx context.Context, id string, update bson.M) (*Context, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	update["$set"] = bson.M{"updated_at": time.Now()}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var context Context
+	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, update, opts).Decode(&context)
+	if err != nil {
+		return nil, err
+	}
+
+	return &context, nil
+}
+
+// ListByModel retrieves the contexts linked to a model with pagination
+func (r *ContextRepository) ListByModel(ctx context.Context, modelID string, pageSize int32, pageToken string) ([]*Context, string, error) {
+	filter := bson.M{"model_ids": modelID}
+	if pageToken != "" {
+		objectID, err := primitive.ObjectIDFromHex(pageToken)
+		if err == nil {
+			filter["_id"] = bson.M{"$gt": objectID}
+		}
+	}
+
+	opts := options.Find().SetLimit(int64(pageSize))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	var contexts []*Context
+	if err = cursor.All(ctx, &contexts); err != nil {
+		return nil, "", err
+	}
+
+	var nextPageToken string
+	if len(contexts) == int(pageSize) {
+		nextPageToken = contexts[len(contexts)-1].ID.Hex()
+	}
+
+	return contexts, nextPageToken, nil
+}
@@ -37,8 +37,12 @@ func (r *DataRepository) Add(ctx context.Context, data *Data) error {
 	data.CreatedAt = time.Now()
 	data.UpdatedAt = time.Now()
 	
-	_, err := r.collection.InsertOne(ctx, data)
-	return err
+	result, err := r.collection.InsertOne(ctx, data)
+	if err != nil {
+		return err
+	}
+	data.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
 }
 
 // Get retrieves data by ID
@@ -90,6 +94,31 @@ func (r *DataRepository) List(ctx context.Context, dataType string, pageSize int
 	return data, nextPageToken, nil
 }
 
+// Find retrieves up to limit data items of a type whose metadata contains
+// every key/value pair of metadata, oldest first
+func (r *DataRepository) Find(ctx context.Context, dataType string, metadata map[string]string, limit int64) ([]*Data, error) {
+	filter := bson.M{}
+	if dataType != "" {
+		filter["type"] = dataType
+	}
+	for k, v := range metadata {
+		filter["metadata."+k] = v
+	}
+
+	opts := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(limit)
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var data []*Data
+	if err = cursor.All(ctx, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
 // Delete removes data by ID
 func (r *DataRepository) Delete(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Model health statuses
+const (
+	StatusUnknown   = "unknown"
+	StatusHealthy   = "healthy"
+	StatusUnhealthy = "unhealthy"
+)
+
+// Check is the outcome of one probe of a model's backend
+type Check struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ModelID   string             `bson:"model_id" json:"model_id"`
+	OK        bool               `bson:"ok" json:"ok"`
+	LatencyMs int64              `bson:"latency_ms" json:"latency_ms"`
+	Error     string             `bson:"error" json:"error"`
+	CheckedAt time.Time          `bson:"checked_at" json:"checked_at"`
+}
+
+// Status is the current health of a model. A model is unhealthy once its
+// probes fail a threshold of times in a row, and healthy again as soon as
+// one succeeds.
+type Status struct {
+	ModelID             string    `bson:"_id" json:"model_id"`
+	Status              string    `bson:"status" json:"status"`
+	ConsecutiveFailures int       `bson:"consecutive_failures" json:"consecutive_failures"`
+	LastCheck           Check     `bson:"last_check" json:"last_check"`
+	UpdatedAt           time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// HealthRepository handles database operations for model health
+type HealthRepository struct {
+	checks   *mongo.Collection
+	statuses *mongo.Collection
+}
+
+// NewHealthRepository creates a new HealthRepository
+func NewHealthRepository(db *mongo.Database) *HealthRepository {
+	return &HealthRepository{
+		checks:   db.Collection("model_health_checks"),
+		statuses: db.Collection("model_health"),
+	}
+}
+
+// EnsureIndexes keeps the check history for retention
+func (r *HealthRepository) EnsureIndexes(ctx context.Context, retention time.Duration) error {
+	_, err := r.checks.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"checked_at": 1}, Options: options.Index().SetExpireAfterSeconds(int32(retention.Seconds()))},
+		{Keys: bson.D{{Key: "model_id", Value: 1}, {Key: "checked_at", Value: -1}}},
+	})
+	return err
+}
+
+// Record stores a check and updates the model's status, which turns
+// unhealthy after threshold failures in a row
+func (r *HealthRepository) Record(ctx context.Context, check *Check, threshold int) (*Status, error) {
+	if _, err := r.checks.InsertOne(ctx, check); err != nil {
+		return nil, err
+	}
+
+	status, err := r.Get(ctx, check.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	if check.OK {
+		status.ConsecutiveFailures = 0
+		status.Status = StatusHealthy
+	} else {
+		status.ConsecutiveFailures++
+		if status.ConsecutiveFailures >= threshold {
+			status.Status = StatusUnhealthy
+		}
+	}
+	status.LastCheck = *check
+	status.UpdatedAt = time.Now()
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.statuses.ReplaceOne(ctx, bson.M{"_id": status.ModelID}, status, opts); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Get returns a model's status, which is unknown until it is first probed
+func (r *HealthRepository) Get(ctx context.Context, modelID string) (*Status, error) {
+	var status Status
+	err := r.statuses.FindOne(ctx, bson.M{"_id": modelID}).Decode(&status)
+	if err == mongo.ErrNoDocuments {
+		return &Status{ModelID: modelID, Status: StatusUnknown}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// List returns the status of every probed model
+func (r *HealthRepository) List(ctx context.Context) ([]*Status, error) {
+	cursor, err := r.statuses.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var statuses []*Status
+	if err := cursor.All(ctx, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// History returns a model's latest checks, newest first
+func (r *HealthRepository) History(ctx context.Context, modelID string, limit int64) ([]*Check, error) {
+	opts := options.Find().SetSort(bson.M{"checked_at": -1}).SetLimit(limit)
+	cursor, err := r.checks.Find(ctx, bson.M{"model_id": modelID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var checks []*Check
+	if err := cursor.All(ctx, &checks); err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
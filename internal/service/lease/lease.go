@@ -0,0 +1,60 @@
+package lease
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Lease is a named, expiring lock held by one server at a time, used to
+// elect a leader among the servers sharing a database
+type Lease struct {
+	Name      string    `bson:"_id" json:"name"`
+	Owner     string    `bson:"owner" json:"owner"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// LeaseRepository handles database operations for leases
+type LeaseRepository struct {
+	collection *mongo.Collection
+}
+
+// NewLeaseRepository creates a new LeaseRepository
+func NewLeaseRepository(db *mongo.Database) *LeaseRepository {
+	return &LeaseRepository{
+		collection: db.Collection("leases"),
+	}
+}
+
+// Acquire takes or renews the named lease for owner until ttl from now. It
+// reports false while another owner holds an unexpired lease.
+func (r *LeaseRepository) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": []bson.M{
+			{"owner": owner},
+			{"expires_at": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "expires_at": now.Add(ttl)}}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// The lease exists and is held by someone else
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Release gives up the named lease if owner holds it
+func (r *LeaseRepository) Release(ctx context.Context, name, owner string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": name, "owner": owner})
+	return err
+}
@@ -2,6 +2,9 @@ package model
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -10,6 +13,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// TypeRouter is the type of models that route each call to one of the
+// models listed in their "models" parameter
+const TypeRouter = "router"
+
 // Model represents a machine learning model in the system
 type Model struct {
 	ID          primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
@@ -17,29 +24,51 @@ type Model struct {
 	Type        string                 `bson:"type" json:"type"`
 	Description string                 `bson:"description" json:"description"`
 	Parameters  map[string]string      `bson:"parameters" json:"parameters"`
+	Version     int                    `bson:"version" json:"version"`
 	CreatedAt   time.Time              `bson:"created_at" json:"created_at"`
 	UpdatedAt   time.Time              `bson:"updated_at" json:"updated_at"`
 }
 
+// APIKeyEnvs are the environment variables the "api_key_env" parameter may
+// name. Other variables, such as the secrets key, are never read as API
+// keys.
+var APIKeyEnvs = []string{"OPENAI_API_KEY", "ANTHROPIC_API_KEY"}
+
 // ModelRepository handles database operations for models
 type ModelRepository struct {
 	collection *mongo.Collection
+	versions   *mongo.Collection
+	aliases    *mongo.Collection
 }
 
 // NewModelRepository creates a new ModelRepository
 func NewModelRepository(db *mongo.Database) *ModelRepository {
 	return &ModelRepository{
 		collection: db.Collection("models"),
+		versions:   db.Collection("model_versions"),
+		aliases:    db.Collection("model_aliases"),
 	}
 }
 
-// Create creates a new model
+// Create creates a new model as its version 1
 func (r *ModelRepository) Create(ctx context.Context, model *Model) error {
+	if strings.Contains(model.Name, "@") {
+		return fmt.Errorf("model name %q may not contain @", model.Name)
+	}
+	schema := SchemaFor(model.Type)
+	model.Parameters = schema.WithDefaults(model.Parameters)
+	if err := schema.Validate(model.Parameters); err != nil {
+		return err
+	}
+	model.ID = primitive.NewObjectID()
+	model.Version = 1
 	model.CreatedAt = time.Now()
-	model.UpdatedAt = time.Now()
+	model.UpdatedAt = model.CreatedAt
 	
-	_, err := r.collection.InsertOne(ctx, model)
-	return err
+	if _, err := r.collection.InsertOne(ctx, model); err != nil {
+		return err
+	}
+	return r.insertVersion(ctx, model)
 }
 
 // Get retrieves a model by ID
@@ -86,4 +115,55 @@ func (r *ModelRepository) List(ctx context.Context, pageSize int32, pageToken st
 	}
 
 	return models, nextPageToken, nil
-}
\ No newline at end of file
+}
+
+// ContextWindow returns the number of tokens the model accepts, or zero
+// when the "context_window" parameter is not set
+func (m *Model) ContextWindow() int {
+	window, err := strconv.Atoi(m.Parameters["context_window"])
+	if err != nil {
+		return 0
+	}
+	return window
+}
+
+// PromptBudget returns the tokens left for the prompt once "max_tokens" are
+// reserved for the completion, or zero when the context window is unknown
+func (m *Model) PromptBudget() int {
+	window := m.ContextWindow()
+	if window == 0 {
+		return 0
+	}
+
+	maxTokens, _ := strconv.Atoi(m.Parameters["max_tokens"])
+	if maxTokens <= 0 || maxTokens >= window {
+		return window
+	}
+	return window - maxTokens
+}
+
+// Cost prices a provider call from the "prompt_price_per_1k" and
+// "completion_price_per_1k" parameters, the price of a thousand tokens.
+// Models without prices cost nothing.
+func (m *Model) Cost(promptTokens, completionTokens int) float64 {
+	promptPrice, _ := strconv.ParseFloat(m.Parameters["prompt_price_per_1k"], 64)
+	completionPrice, _ := strconv.ParseFloat(m.Parameters["completion_price_per_1k"], 64)
+	return (float64(promptTokens)*promptPrice + float64(completionTokens)*completionPrice) / 1000
+}
+
+// IsRouter reports whether the model routes calls to other models
+func (m *Model) IsRouter() bool {
+	return m.Type == TypeRouter
+}
+
+// Routes returns the IDs of the models a router falls back between, in
+// the order of its comma separated "models" parameter
+func (m *Model) Routes() []string {
+	var ids []string
+	for _, id := range strings.Split(m.Parameters["models"], ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
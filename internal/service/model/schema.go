@@ -0,0 +1,238 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/DavutcanJ/mongo-mcp-server/internal/tokenizer"
+)
+
+// Parameter types
+const (
+	ParamString  = "string"
+	ParamNumber  = "number"
+	ParamInteger = "integer"
+	ParamBoolean = "boolean"
+)
+
+// Param declares the type and allowed values of a model parameter.
+// Parameters are stored as strings; Param decides which strings are valid
+// and what typed value they stand for. Model-only parameters, such as the
+// endpoint and credentials, cannot be overridden by executions.
+type Param struct {
+	Type        string   `json:"type"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Description string   `json:"description,omitempty"`
+	ModelOnly   bool     `json:"modelOnly,omitempty"`
+}
+
+// Schema declares the parameters of a model type. Parameters it does not
+// declare are passed through unchecked.
+type Schema map[string]Param
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+// commonSchema declares the parameters every model type understands
+var commonSchema = Schema{
+	"provider":                {Type: ParamString, ModelOnly: true, Description: "provider serving the model, inferred from the model name if unset"},
+	"model":                   {Type: ParamString, Description: "name the backend knows the model by, the type if unset"},
+	"base_url":                {Type: ParamString, ModelOnly: true, Description: "endpoint of a compatible server"},
+	"api_key_env":             {Type: ParamString, ModelOnly: true, Enum: APIKeyEnvs, Description: "environment variable holding the API key"},
+	"api_key_secret":          {Type: ParamString, ModelOnly: true, Description: "secret holding the API key, taking precedence over api_key_env"},
+	"temperature":             {Type: ParamNumber, Min: bound(0), Max: bound(2)},
+	"max_tokens":              {Type: ParamInteger, Min: bound(1), Description: "completion tokens"},
+	"context_window":          {Type: ParamInteger, Min: bound(1), Description: "tokens the model accepts"},
+	"tokenizer":               {Type: ParamString, Enum: []string{tokenizer.CL100KBase, tokenizer.Estimate}},
+	"truncation_policy":       {Type: ParamString, Enum: []string{tokenizer.PolicyFail, tokenizer.PolicyTruncate}},
+	"timeout_seconds":         {Type: ParamInteger, Min: bound(1)},
+	"cache":                   {Type: ParamString, Enum: []string{"always", "deterministic"}},
+	"cache_ttl_seconds":       {Type: ParamInteger, Min: bound(1)},
+	"prompt_price_per_1k":     {Type: ParamNumber, Min: bound(0)},
+	"completion_price_per_1k": {Type: ParamNumber, Min: bound(0)},
+	"health_check":            {Type: ParamBoolean, Description: "whether the model is probed, true if unset"},
+}
+
+var (
+	schemasMu sync.RWMutex
+	schemas   = map[string]Schema{
+		TypeRouter: {
+			"models":  {Type: ParamString, Required: true, Description: "comma separated references of the models to route to"},
+			"routing": {Type: ParamString, Enum: []string{"fallback", "cheapest", "input_length", "round_robin"}, Default: "fallback"},
+		},
+	}
+)
+
+// RegisterSchema declares the parameters of a model type, in addition to
+// the ones common to all types
+func RegisterSchema(modelType string, schema Schema) error {
+	for name, p := range schema {
+		switch p.Type {
+		case ParamString, ParamNumber, ParamInteger, ParamBoolean:
+		default:
+			return fmt.Errorf("parameter %s of model type %s has invalid type %q", name, modelType, p.Type)
+		}
+		if p.Default != "" {
+			if err := p.Check(p.Default); err != nil {
+				return fmt.Errorf("default of parameter %s of model type %s: %v", name, modelType, err)
+			}
+		}
+	}
+
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+	schemas[modelType] = schema
+	return nil
+}
+
+// SchemaFor returns the parameters of a model type: the common ones and
+// those registered for the type
+func SchemaFor(modelType string) Schema {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+
+	schema := make(Schema, len(commonSchema)+len(schemas[modelType]))
+	for name, p := range commonSchema {
+		schema[name] = p
+	}
+	for name, p := range schemas[modelType] {
+		schema[name] = p
+	}
+	return schema
+}
+
+// Check reports whether value is valid for the parameter
+func (p Param) Check(value string) error {
+	switch p.Type {
+	case ParamNumber, ParamInteger:
+		var v float64
+		var err error
+		if p.Type == ParamInteger {
+			var i int64
+			i, err = strconv.ParseInt(value, 10, 64)
+			v = float64(i)
+		} else {
+			v, err = strconv.ParseFloat(value, 64)
+		}
+		if err != nil {
+			return fmt.Errorf("%q is not of type %s", value, p.Type)
+		}
+		if p.Min != nil && v < *p.Min {
+			return fmt.Errorf("%s is below the minimum of %s", value, strconv.FormatFloat(*p.Min, 'f', -1, 64))
+		}
+		if p.Max != nil && v > *p.Max {
+			return fmt.Errorf("%s is above the maximum of %s", value, strconv.FormatFloat(*p.Max, 'f', -1, 64))
+		}
+	case ParamBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+	}
+
+	if len(p.Enum) > 0 {
+		for _, allowed := range p.Enum {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q must be one of %v", value, p.Enum)
+	}
+	return nil
+}
+
+// Value returns the typed value a valid parameter string stands for
+func (p Param) Value(value string) interface{} {
+	switch p.Type {
+	case ParamNumber, ParamInteger:
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case ParamBoolean:
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// Validate checks the parameters of a model: required ones must be set and
+// declared ones valid
+func (s Schema) Validate(params map[string]string) error {
+	problems := s.check(params)
+	for name, p := range s {
+		if _, ok := params[name]; p.Required && !ok {
+			problems = append(problems, fmt.Sprintf("%s is required", name))
+		}
+	}
+	return invalid(problems)
+}
+
+// ValidateOverrides checks the declared parameters among params, such as
+// the parameters an execution overrides its model's with. Model-only
+// parameters cannot be overridden.
+func (s Schema) ValidateOverrides(params map[string]string) error {
+	problems := s.check(params)
+	for name := range params {
+		if s[name].ModelOnly {
+			problems = append(problems, fmt.Sprintf("%s can only be set on the model", name))
+		}
+	}
+	return invalid(problems)
+}
+
+// check lists the declared parameters among params that are invalid
+func (s Schema) check(params map[string]string) []string {
+	var problems []string
+	for name, value := range params {
+		if p, ok := s[name]; ok {
+			if err := p.Check(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+	}
+	return problems
+}
+
+func invalid(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("invalid parameters: %s", strings.Join(problems, "; "))
+}
+
+// WithDefaults returns params with the defaults of the parameters it does
+// not set
+func (s Schema) WithDefaults(params map[string]string) map[string]string {
+	result := make(map[string]string, len(params))
+	for name, value := range params {
+		result[name] = value
+	}
+	for name, p := range s {
+		if _, ok := result[name]; !ok && p.Default != "" {
+			result[name] = p.Default
+		}
+	}
+	return result
+}
+
+// Typed returns params as typed values
+func (s Schema) Typed(params map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		if p, ok := s[name]; ok {
+			result[name] = p.Value(value)
+		} else {
+			result[name] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParamCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		param Param
+		value string
+		err   string
+	}{
+		{"number", Param{Type: ParamNumber}, "0.5", ""},
+		{"number not a number", Param{Type: ParamNumber}, "hot", `"hot" is not of type number`},
+		{"number at minimum", Param{Type: ParamNumber, Min: bound(0)}, "0", ""},
+		{"number below minimum", Param{Type: ParamNumber, Min: bound(0)}, "-0.1", "-0.1 is below the minimum of 0"},
+		{"number above maximum", Param{Type: ParamNumber, Max: bound(2)}, "3", "3 is above the maximum of 2"},
+		{"integer", Param{Type: ParamInteger, Min: bound(1)}, "42", ""},
+		{"integer with fraction", Param{Type: ParamInteger}, "1.5", `"1.5" is not of type integer`},
+		{"boolean", Param{Type: ParamBoolean}, "true", ""},
+		{"boolean invalid", Param{Type: ParamBoolean}, "maybe", `"maybe" is not a boolean`},
+		{"enum", Param{Type: ParamString, Enum: []string{"a", "b"}}, "b", ""},
+		{"enum invalid", Param{Type: ParamString, Enum: []string{"a", "b"}}, "c", `"c" must be one of [a b]`},
+		{"string", Param{Type: ParamString}, "anything", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.param.Check(tt.value)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("Check(%q) = %v, want nil", tt.value, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("Check(%q) = %v, want %q", tt.value, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema := SchemaFor(TypeRouter)
+	tests := []struct {
+		name   string
+		params map[string]string
+		err    string
+	}{
+		{"valid", map[string]string{"models": "a,b", "temperature": "1"}, ""},
+		{"missing required", map[string]string{"temperature": "1"}, "models is required"},
+		{"invalid common", map[string]string{"models": "a", "temperature": "3"}, "temperature: 3 is above the maximum of 2"},
+		{"undeclared passed through", map[string]string{"models": "a", "custom": "x"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkError(t, schema.Validate(tt.params), tt.err)
+		})
+	}
+}
+
+func TestSchemaValidateOverrides(t *testing.T) {
+	schema := SchemaFor("gpt-4")
+	tests := []struct {
+		name   string
+		params map[string]string
+		err    string
+	}{
+		{"valid", map[string]string{"temperature": "0.2", "max_tokens": "100"}, ""},
+		{"required not needed", map[string]string{}, ""},
+		{"invalid type", map[string]string{"max_tokens": "many"}, `max_tokens: "many" is not of type integer`},
+		{"base_url", map[string]string{"base_url": "https://example.com"}, "base_url can only be set on the model"},
+		{"api_key_env", map[string]string{"api_key_env": "OPENAI_API_KEY"}, "api_key_env can only be set on the model"},
+		{"api_key_secret", map[string]string{"api_key_secret": "openai"}, "api_key_secret can only be set on the model"},
+		{"provider", map[string]string{"provider": "echo"}, "provider can only be set on the model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkError(t, schema.ValidateOverrides(tt.params), tt.err)
+		})
+	}
+}
+
+func TestRegisterSchema(t *testing.T) {
+	if err := RegisterSchema("test-invalid-type", Schema{"p": {Type: "float"}}); err == nil {
+		t.Error("RegisterSchema accepted an invalid type")
+	}
+	if err := RegisterSchema("test-invalid-default", Schema{"p": {Type: ParamInteger, Default: "x"}}); err == nil {
+		t.Error("RegisterSchema accepted an invalid default")
+	}
+	if err := RegisterSchema("test-type", Schema{"top_p": {Type: ParamNumber, Max: bound(1), Default: "1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := SchemaFor("test-type")
+	if _, ok := schema["temperature"]; !ok {
+		t.Error("registered schema lacks the common parameters")
+	}
+	if got := schema.WithDefaults(nil)["top_p"]; got != "1" {
+		t.Errorf("default of top_p = %q, want 1", got)
+	}
+	typed := schema.Typed(map[string]string{"top_p": "0.5", "custom": "x"})
+	if typed["top_p"] != 0.5 || typed["custom"] != "x" {
+		t.Errorf("Typed = %v", typed)
+	}
+}
+
+func checkError(t *testing.T, err error, want string) {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Fatalf("error = %v, want nil", err)
+		}
+		return
+	}
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("error = %v, want it to contain %q", err, want)
+	}
+}
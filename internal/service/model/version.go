@@ -0,0 +1,273 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// aliasPattern is what alias labels look like. Labels of the form "v<n>"
+// are reserved for referring to version n.
+var (
+	aliasPattern   = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	versionPattern = regexp.MustCompile(`^v([0-9]+)$`)
+)
+
+// Version is an immutable snapshot of a model's configuration
+type Version struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ModelID     string             `bson:"model_id" json:"model_id"`
+	Number      int                `bson:"number" json:"number"`
+	Type        string             `bson:"type" json:"type"`
+	Description string             `bson:"description" json:"description"`
+	Parameters  map[string]string  `bson:"parameters" json:"parameters"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Alias is a mutable name for one version of a model, referred to as
+// "<model name>@<alias>"
+type Alias struct {
+	Ref       string    `bson:"_id" json:"ref"`
+	ModelID   string    `bson:"model_id" json:"model_id"`
+	Alias     string    `bson:"alias" json:"alias"`
+	Version   int       `bson:"version" json:"version"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// EnsureIndexes keeps version numbers unique per model
+func (r *ModelRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.versions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "model_id", Value: 1}, {Key: "number", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.aliases.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.M{"model_id": 1},
+	})
+	return err
+}
+
+// CreateVersion makes the given configuration the model's current one,
+// as a new version, and returns the updated model. Earlier versions are
+// kept unchanged.
+func (r *ModelRepository) CreateVersion(ctx context.Context, id, modelType, description string, parameters map[string]string) (*Model, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	schema := SchemaFor(modelType)
+	parameters = schema.WithDefaults(parameters)
+	if err := schema.Validate(parameters); err != nil {
+		return nil, err
+	}
+
+	update := bson.M{
+		"$inc": bson.M{"version": 1},
+		"$set": bson.M{
+			"type":        modelType,
+			"description": description,
+			"parameters":  parameters,
+			"updated_at":  time.Now(),
+		},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var m Model
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, update, opts).Decode(&m); err != nil {
+		return nil, err
+	}
+
+	if err := r.insertVersion(ctx, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// insertVersion snapshots the model's current configuration
+func (r *ModelRepository) insertVersion(ctx context.Context, m *Model) error {
+	_, err := r.versions.InsertOne(ctx, &Version{
+		ModelID:     m.ID.Hex(),
+		Number:      m.Version,
+		Type:        m.Type,
+		Description: m.Description,
+		Parameters:  m.Parameters,
+		CreatedAt:   m.UpdatedAt,
+	})
+	return err
+}
+
+// GetVersion returns a model as configured at the given version, or its
+// current configuration when version is zero
+func (r *ModelRepository) GetVersion(ctx context.Context, id string, version int) (*Model, error) {
+	m, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 || version == m.Version {
+		return m, nil
+	}
+
+	var v Version
+	err = r.versions.FindOne(ctx, bson.M{"model_id": id, "number": version}).Decode(&v)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("model %s has no version %d", id, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m.Version = v.Number
+	m.Type = v.Type
+	m.Description = v.Description
+	m.Parameters = v.Parameters
+	m.UpdatedAt = v.CreatedAt
+	return m, nil
+}
+
+// ListVersions returns the versions of a model, oldest first
+func (r *ModelRepository) ListVersions(ctx context.Context, id string) ([]*Version, error) {
+	opts := options.Find().SetSort(bson.M{"number": 1})
+	cursor, err := r.versions.Find(ctx, bson.M{"model_id": id}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var versions []*Version
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// SetAlias points an alias of a model at one of its versions, or at its
+// current version when version is zero. Moving an alias is how a model is
+// rolled forward or back.
+func (r *ModelRepository) SetAlias(ctx context.Context, id, alias string, version int) (*Alias, error) {
+	if !aliasPattern.MatchString(alias) || versionPattern.MatchString(alias) {
+		return nil, fmt.Errorf("invalid alias %q: use lowercase letters, digits, - and _, and not v<number>", alias)
+	}
+
+	m, err := r.GetVersion(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Alias{
+		Ref:       m.Name + "@" + alias,
+		ModelID:   id,
+		Alias:     alias,
+		Version:   m.Version,
+		UpdatedAt: time.Now(),
+	}
+	// The reference is unique, so it may not move to another model of the
+	// same name
+	filter := bson.M{"_id": a.Ref, "model_id": id}
+	_, err = r.aliases.ReplaceOne(ctx, filter, a, options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("alias %s belongs to another model", a.Ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListAliases returns the aliases of a model
+func (r *ModelRepository) ListAliases(ctx context.Context, id string) ([]*Alias, error) {
+	opts := options.Find().SetSort(bson.M{"alias": 1})
+	cursor, err := r.aliases.Find(ctx, bson.M{"model_id": id}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var aliases []*Alias
+	if err := cursor.All(ctx, &aliases); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// DeleteAlias removes an alias of a model
+func (r *ModelRepository) DeleteAlias(ctx context.Context, id, alias string) error {
+	result, err := r.aliases.DeleteOne(ctx, bson.M{"model_id": id, "alias": alias})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("model %s has no alias %q", id, alias)
+	}
+	return nil
+}
+
+// Resolve returns the model version a reference points to. References are
+// a model ID or name, for its current version, optionally followed by
+// "@<alias>" or "@v<number>".
+func (r *ModelRepository) Resolve(ctx context.Context, ref string) (*Model, error) {
+	name, label, versioned := strings.Cut(ref, "@")
+	if !versioned {
+		return r.getByIDOrName(ctx, ref)
+	}
+
+	if match := versionPattern.FindStringSubmatch(label); match != nil {
+		m, err := r.getByIDOrName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		version, _ := strconv.Atoi(match[1])
+		return r.GetVersion(ctx, m.ID.Hex(), version)
+	}
+
+	var a Alias
+	err := r.aliases.FindOne(ctx, bson.M{"_id": ref}).Decode(&a)
+	if err == mongo.ErrNoDocuments {
+		// Aliases are named after the model, but a model ID works too
+		if m, idErr := r.Get(ctx, name); idErr == nil {
+			err = r.aliases.FindOne(ctx, bson.M{"model_id": m.ID.Hex(), "alias": label}).Decode(&a)
+		}
+	}
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("model alias %s not found", ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetVersion(ctx, a.ModelID, a.Version)
+}
+
+// getByIDOrName returns the model with the given ID, or else the only
+// model with the given name
+func (r *ModelRepository) getByIDOrName(ctx context.Context, ref string) (*Model, error) {
+	if primitive.IsValidObjectID(ref) {
+		return r.Get(ctx, ref)
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"name": ref}, options.Find().SetLimit(2))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var models []*Model
+	if err := cursor.All(ctx, &models); err != nil {
+		return nil, err
+	}
+	switch len(models) {
+	case 0:
+		return nil, fmt.Errorf("model %s not found", ref)
+	case 1:
+		return models[0], nil
+	default:
+		return nil, fmt.Errorf("model name %s is ambiguous, use the model ID", ref)
+	}
+}
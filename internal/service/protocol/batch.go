@@ -0,0 +1,185 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Batch groups the executions of one request over many inputs
+type Batch struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProtocolID string             `bson:"protocol_id" json:"protocol_id"`
+	Type       string             `bson:"type" json:"type"`
+	ModelID    string             `bson:"model_id" json:"model_id"`
+	ContextID  string             `bson:"context_id" json:"context_id"`
+	Parameters map[string]string  `bson:"parameters" json:"parameters"`
+	Priority   string             `bson:"priority" json:"priority"`
+	Tenant     string             `bson:"tenant" json:"tenant"`
+	Total      int                `bson:"total" json:"total"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// BatchProgress counts a batch's executions by status
+type BatchProgress struct {
+	Total     int64
+	Pending   int64
+	Running   int64
+	Completed int64
+	Failed    int64
+	Cancelled int64
+}
+
+// Status returns pending until an execution of the batch starts, running
+// until they have all finished, and completed after
+func (p BatchProgress) Status() string {
+	switch {
+	case p.Pending == p.Total:
+		return StatusPending
+	case p.Pending+p.Running > 0:
+		return StatusRunning
+	default:
+		return StatusCompleted
+	}
+}
+
+// Percent returns the percentage of the batch's executions that finished
+func (p BatchProgress) Percent() float64 {
+	if p.Total == 0 {
+		return 100
+	}
+	return float64(p.Completed+p.Failed+p.Cancelled) * 100 / float64(p.Total)
+}
+
+// CreateBatch records a batch and queues its executions, one per element
+// of executions, as pending. When they cannot all be queued, the batch and
+// those that were are removed again.
+func (r *ProtocolRepository) CreateBatch(ctx context.Context, batch *Batch, executions []*Execution) error {
+	batch.Total = len(executions)
+	batch.CreatedAt = time.Now()
+	batch.UpdatedAt = time.Now()
+
+	result, err := r.batches.InsertOne(ctx, batch)
+	if err != nil {
+		return err
+	}
+	batch.ID = result.InsertedID.(primitive.ObjectID)
+
+	docs := make([]interface{}, len(executions))
+	for i, execution := range executions {
+		execution.ID = primitive.NewObjectID()
+		execution.BatchID = batch.ID.Hex()
+		execution.BatchIndex = i
+		execution.Status = StatusPending
+		execution.CreatedAt = batch.CreatedAt
+		execution.UpdatedAt = batch.CreatedAt
+		docs[i] = execution
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	if _, err := r.executions.InsertMany(ctx, docs); err != nil {
+		if discardErr := r.discardBatch(batch.ID); discardErr != nil {
+			return fmt.Errorf("%v; removing the batch: %v", err, discardErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// discardBatch removes a batch whose executions could not all be queued,
+// with the executions that were. The request's context may be the reason
+// they could not, so a fresh one is used.
+func (r *ProtocolRepository) discardBatch(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := r.executions.DeleteMany(ctx, bson.M{"batch_id": id.Hex(), "status": StatusPending}); err != nil {
+		return err
+	}
+	_, err := r.batches.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// GetBatch retrieves a batch by ID
+func (r *ProtocolRepository) GetBatch(ctx context.Context, id string) (*Batch, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var batch Batch
+	err = r.batches.FindOne(ctx, bson.M{"_id": objectID}).Decode(&batch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+// GetBatchProgress counts the executions of a batch by status
+func (r *ProtocolRepository) GetBatchProgress(ctx context.Context, batch *Batch) (*BatchProgress, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"batch_id": batch.ID.Hex()}}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := r.executions.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var counts []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+
+	progress := &BatchProgress{Total: int64(batch.Total)}
+	for _, c := range counts {
+		switch c.Status {
+		case StatusPending:
+			progress.Pending = c.Count
+		case StatusRunning:
+			progress.Running = c.Count
+		case StatusCompleted:
+			progress.Completed = c.Count
+		case StatusFailed:
+			progress.Failed = c.Count
+		case StatusCancelled:
+			progress.Cancelled = c.Count
+		}
+	}
+	return progress, nil
+}
+
+// EachBatchExecution calls fn with the executions of a batch in input order
+func (r *ProtocolRepository) EachBatchExecution(ctx context.Context, batchID string, fn func(*Execution) error) error {
+	opts := options.Find().SetSort(bson.M{"batch_index": 1})
+	cursor, err := r.executions.Find(ctx, bson.M{"batch_id": batchID}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var execution Execution
+		if err := cursor.Decode(&execution); err != nil {
+			return err
+		}
+		if err := fn(&execution); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
@@ -0,0 +1,35 @@
+package protocol
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestCreateBatchRemovesFailedBatch(t *testing.T) {
+	repo := testRepository(t)
+	ctx := context.Background()
+
+	// Make the second execution fail to insert after the first one was
+	index := mongo.IndexModel{Keys: bson.M{"input": 1}, Options: options.Index().SetUnique(true)}
+	if _, err := repo.executions.Indexes().CreateOne(ctx, index); err != nil {
+		t.Fatalf("CreateOne: %v", err)
+	}
+	executions := []*Execution{{Input: "same"}, {Input: "same"}}
+	if err := repo.CreateBatch(ctx, &Batch{}, executions); err == nil {
+		t.Fatal("CreateBatch succeeded with a duplicate execution")
+	}
+
+	for name, collection := range map[string]*mongo.Collection{"batches": repo.batches, "executions": repo.executions} {
+		n, err := collection.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			t.Fatalf("CountDocuments: %v", err)
+		}
+		if n != 0 {
+			t.Errorf("%d %s left after the failed batch, want none", n, name)
+		}
+	}
+}
@@ -2,20 +2,49 @@ package protocol
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Types lists the supported protocol types
+var Types = []string{"GENERATE", "CHAT", "COMPLETE", "EDIT", "ANALYZE"}
+
+// Execution and step statuses
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+	StatusCancelled = "cancelled"
+)
+
+// Execution priorities. Interactive executions get a larger share of the
+// workers than batch ones.
+const (
+	PriorityInteractive = "interactive"
+	PriorityBatch       = "batch"
+)
+
+// Priorities lists the supported execution priorities
+var Priorities = []string{PriorityInteractive, PriorityBatch}
+
+// DefaultTenant owns executions submitted without a tenant
+const DefaultTenant = "default"
+
 // Protocol represents a protocol in the system
 type Protocol struct {
 	ID          primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
 	Name        string                 `bson:"name" json:"name"`
 	Type        string                 `bson:"type" json:"type"`
 	Description string                 `bson:"description" json:"description"`
-	Steps       []string              `bson:"steps" json:"steps"`
+	Steps       []Step                 `bson:"steps" json:"steps"`
 	Parameters  map[string]string      `bson:"parameters" json:"parameters"`
 	CreatedAt   time.Time              `bson:"created_at" json:"created_at"`
 	UpdatedAt   time.Time              `bson:"updated_at" json:"updated_at"`
@@ -23,25 +52,203 @@ type Protocol struct {
 
 // Execution represents a protocol execution
 type Execution struct {
-	ID          primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
-	ProtocolID  string                 `bson:"protocol_id" json:"protocol_id"`
-	ContextID   string                 `bson:"context_id" json:"context_id"`
-	Status      string                 `bson:"status" json:"status"`
-	Result      string                 `bson:"result" json:"result"`
-	Metadata    map[string]string      `bson:"metadata" json:"metadata"`
-	CreatedAt   time.Time              `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time              `bson:"updated_at" json:"updated_at"`
+	ID              primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	ProtocolID      string                 `bson:"protocol_id" json:"protocol_id"`
+	Type            string                 `bson:"type" json:"type"`
+	ModelID         string                 `bson:"model_id" json:"model_id"`
+	ModelVersion    int                    `bson:"model_version" json:"model_version"`
+	ModelRef        string                 `bson:"model_ref,omitempty" json:"model_ref,omitempty"`
+	Models          []ModelPin             `bson:"models,omitempty" json:"models,omitempty"`
+	ContextID       string                 `bson:"context_id" json:"context_id"`
+	Input           string                 `bson:"input" json:"input"`
+	Priority        string                 `bson:"priority" json:"priority"`
+	Tenant          string                 `bson:"tenant" json:"tenant"`
+	APIKeyID        string                 `bson:"api_key_id" json:"api_key_id"`
+	TraceContext    map[string]string      `bson:"trace_context,omitempty" json:"-"`
+	BatchID         string                 `bson:"batch_id,omitempty" json:"batch_id,omitempty"`
+	BatchIndex      int                    `bson:"batch_index,omitempty" json:"batch_index,omitempty"`
+	Context         string                 `bson:"context" json:"context"`
+	Status          string                 `bson:"status" json:"status"`
+	Result          string                 `bson:"result" json:"result"`
+	Error           string                 `bson:"error" json:"error"`
+	Steps           []StepResult           `bson:"steps" json:"steps"`
+	TotalSteps      int                    `bson:"total_steps" json:"total_steps"`
+	Running         []string               `bson:"running" json:"running"`
+	Attempts        []Attempt              `bson:"attempts" json:"attempts"`
+	Usage           Usage                  `bson:"usage" json:"usage"`
+	Cached          bool                   `bson:"cached" json:"cached"`
+	ServedBy        string                 `bson:"served_by,omitempty" json:"served_by,omitempty"`
+	ServedByVersion int                    `bson:"served_by_version,omitempty" json:"served_by_version,omitempty"`
+	Deadline        time.Time              `bson:"deadline" json:"deadline"`
+	LeaseOwner      string                 `bson:"lease_owner" json:"lease_owner"`
+	LeaseExpiry     time.Time              `bson:"lease_expires_at" json:"lease_expires_at"`
+	Claims          int                    `bson:"claims" json:"claims"`
+	StartedAt       time.Time              `bson:"started_at" json:"started_at"`
+	FinishedAt      time.Time              `bson:"finished_at" json:"finished_at"`
+	Metadata        map[string]string      `bson:"metadata" json:"metadata"`
+	CreatedAt       time.Time              `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time              `bson:"updated_at" json:"updated_at"`
+}
+
+// Usage counts the tokens an execution's provider calls consumed and
+// their cost
+type Usage struct {
+	PromptTokens     int     `bson:"prompt_tokens" json:"prompt_tokens"`
+	CompletionTokens int     `bson:"completion_tokens" json:"completion_tokens"`
+	Cost             float64 `bson:"cost" json:"cost"`
+}
+
+// Add accumulates the usage of a provider call
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.Cost += other.Cost
+}
+
+// Restart clears the progress of an execution's steps, to run them again
+// from the first one. Its attempts and their usage are kept: those
+// provider calls were made and are billed.
+func (e *Execution) Restart() {
+	e.Steps = []StepResult{}
+	e.Running = []string{}
+}
+
+// Duration returns how long the execution ran, or has been running
+func (e *Execution) Duration() time.Duration {
+	switch {
+	case e.StartedAt.IsZero():
+		return 0
+	case e.FinishedAt.IsZero():
+		return time.Since(e.StartedAt)
+	default:
+		return e.FinishedAt.Sub(e.StartedAt)
+	}
+}
+
+// Progress returns the percentage of the execution's steps that have
+// finished
+func (e *Execution) Progress() float64 {
+	switch {
+	case e.Status == StatusCompleted:
+		return 100
+	case e.TotalSteps == 0:
+		return 0
+	}
+	done := len(e.Steps)
+	if done > e.TotalSteps {
+		done = e.TotalSteps
+	}
+	return float64(done) * 100 / float64(e.TotalSteps)
+}
+
+// ExecutionFilter selects the executions to list. Zero fields match all.
+type ExecutionFilter struct {
+	ModelID       string
+	ContextID     string
+	ProtocolID    string
+	Status        string
+	Tenant        string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// Attempt records one provider call made by an execution step
+type Attempt struct {
+	StepID       string    `bson:"step_id" json:"step_id"`
+	ModelID      string    `bson:"model_id" json:"model_id"`
+	ModelVersion int       `bson:"model_version" json:"model_version"`
+	Number       int       `bson:"number" json:"number"`
+	Error        string    `bson:"error" json:"error"`
+	Usage        Usage     `bson:"usage" json:"usage"`
+	StartedAt    time.Time `bson:"started_at" json:"started_at"`
+	FinishedAt   time.Time `bson:"finished_at" json:"finished_at"`
+}
+
+// StepResult records the outcome of one pipeline step of an execution
+type StepResult struct {
+	ID           string    `bson:"id" json:"id"`
+	Type         string    `bson:"type" json:"type"`
+	Status       string    `bson:"status" json:"status"`
+	Output       string    `bson:"output" json:"output"`
+	Error        string    `bson:"error" json:"error"`
+	ModelID      string    `bson:"model_id,omitempty" json:"model_id,omitempty"`
+	ModelVersion int       `bson:"model_version,omitempty" json:"model_version,omitempty"`
+	StartedAt    time.Time `bson:"started_at" json:"started_at"`
+	FinishedAt   time.Time `bson:"finished_at" json:"finished_at"`
+}
+
+// ModelPin records the model version a reference made by an execution's
+// steps or routers resolved to when the execution was planned
+type ModelPin struct {
+	Ref     string `bson:"ref" json:"ref"`
+	ModelID string `bson:"model_id" json:"model_id"`
+	Version int    `bson:"version" json:"version"`
+}
+
+// String returns the pinned version as a reference, "<id>@v<version>"
+func (p ModelPin) String() string {
+	return fmt.Sprintf("%s@v%d", p.ModelID, p.Version)
+}
+
+// Pinned returns the model version ref was pinned to, if it was
+func (e *Execution) Pinned(ref string) (ModelPin, bool) {
+	for _, pin := range e.Models {
+		if pin.Ref == ref {
+			return pin, true
+		}
+	}
+	return ModelPin{}, false
+}
+
+// Finished reports whether an execution status is final
+func Finished(status string) bool {
+	return status == StatusCompleted || status == StatusFailed || status == StatusCancelled
+}
+
+// ValidType reports whether t is one of the supported protocol types
+func ValidType(t string) bool {
+	for _, valid := range Types {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidPriority reports whether p is a supported execution priority
+func ValidPriority(p string) bool {
+	for _, valid := range Priorities {
+		if p == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks a protocol definition before it is stored
+func (p *Protocol) Validate() error {
+	if p.Name == "" {
+		return errors.New("protocol name is required")
+	}
+	if !ValidType(p.Type) {
+		return fmt.Errorf("invalid protocol type %q, must be one of %v", p.Type, Types)
+	}
+	return ValidateSteps(p.Steps)
 }
 
 // ProtocolRepository handles database operations for protocols
 type ProtocolRepository struct {
 	collection *mongo.Collection
+	executions *mongo.Collection
+	batches    *mongo.Collection
 }
 
 // NewProtocolRepository creates a new ProtocolRepository
 func NewProtocolRepository(db *mongo.Database) *ProtocolRepository {
 	return &ProtocolRepository{
 		collection: db.Collection("protocols"),
+		executions: db.Collection("executions"),
+		batches:    db.Collection("batches"),
 	}
 }
 
@@ -70,23 +277,208 @@ func (r *ProtocolRepository) Get(ctx context.Context, id string) (*Protocol, err
 	return &protocol, nil
 }
 
-// ExecuteProtocol executes a protocol with the given context and parameters
-func (r *ProtocolRepository) ExecuteProtocol(ctx context.Context, protocolID, contextID string, parameters map[string]string) (*Execution, error) {
-	execution := &Execution{
-		ProtocolID: protocolID,
-		ContextID:  contextID,
-		Status:     "pending",
-		Metadata:   parameters,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+// List retrieves all protocols with pagination
+func (r *ProtocolRepository) List(ctx context.Context, pageSize int32, pageToken string) ([]*Protocol, string, error) {
+	filter := bson.M{}
+	if pageToken != "" {
+		objectID, err := primitive.ObjectIDFromHex(pageToken)
+		if err == nil {
+			filter["_id"] = bson.M{"$gt": objectID}
+		}
+	}
+
+	opts := options.Find().SetLimit(int64(pageSize))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	var protocols []*Protocol
+	if err = cursor.All(ctx, &protocols); err != nil {
+		return nil, "", err
+	}
+
+	var nextPageToken string
+	if len(protocols) == int(pageSize) {
+		nextPageToken = protocols[len(protocols)-1].ID.Hex()
+	}
+
+	return protocols, nextPageToken, nil
+}
+
+// Update replaces a protocol's definition
+func (r *ProtocolRepository) Update(ctx context.Context, protocol *Protocol) error {
+	protocol.UpdatedAt = time.Now()
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": protocol.ID}, bson.M{"$set": bson.M{
+		"name":        protocol.Name,
+		"type":        protocol.Type,
+		"description": protocol.Description,
+		"steps":       protocol.Steps,
+		"parameters":  protocol.Parameters,
+		"updated_at":  protocol.UpdatedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Delete removes a protocol by ID
+func (r *ProtocolRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
+
+// CreateExecution records a new pending execution
+func (r *ProtocolRepository) CreateExecution(ctx context.Context, execution *Execution) error {
+	execution.Status = StatusPending
+	execution.CreatedAt = time.Now()
+	execution.UpdatedAt = time.Now()
+
+	result, err := r.executions.InsertOne(ctx, execution)
+	if err != nil {
+		return err
+	}
+	execution.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// leaseFields are the execution fields owned by the queue. Saving an
+// execution leaves them alone, so a save cannot roll back a lease the
+// heartbeat renewed since the execution was claimed.
+var leaseFields = []string{"_id", "lease_owner", "lease_expires_at", "claims"}
+
+// progressUpdate builds the update saving an execution's progress
+func progressUpdate(execution *Execution) (bson.M, error) {
+	data, err := bson.Marshal(execution)
+	if err != nil {
+		return nil, err
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(data, &fields); err != nil {
+		return nil, err
 	}
+	for _, field := range leaseFields {
+		delete(fields, field)
+	}
+	return bson.M{"$set": fields}, nil
+}
 
-	// TODO: Implement actual protocol execution logic
-	// This is a placeholder for the actual implementation
-	execution.Status = "completed"
-	execution.Result = "Protocol execution completed successfully"
+// UpdateExecution saves the current state of an execution. It returns
+// ErrLeaseLost if the execution has finished or is leased to another worker.
+func (r *ProtocolRepository) UpdateExecution(ctx context.Context, execution *Execution) error {
+	execution.UpdatedAt = time.Now()
+
+	update, err := progressUpdate(execution)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{
+		"_id":         execution.ID,
+		"lease_owner": execution.LeaseOwner,
+		"status":      bson.M{"$nin": []string{StatusCompleted, StatusFailed, StatusCancelled}},
+	}
+	result, err := r.executions.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
 
-	return execution, nil
+// ListExecutions retrieves executions matching filter with pagination
+func (r *ProtocolRepository) ListExecutions(ctx context.Context, filter ExecutionFilter, pageSize int32, pageToken string) ([]*Execution, string, error) {
+	query := bson.M{}
+	if filter.ModelID != "" {
+		query["model_id"] = filter.ModelID
+	}
+	if filter.ContextID != "" {
+		query["context_id"] = filter.ContextID
+	}
+	if filter.ProtocolID != "" {
+		query["protocol_id"] = filter.ProtocolID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Tenant != "" {
+		query["tenant"] = filter.Tenant
+	}
+	created := bson.M{}
+	if !filter.CreatedAfter.IsZero() {
+		created["$gte"] = filter.CreatedAfter
+	}
+	if !filter.CreatedBefore.IsZero() {
+		created["$lt"] = filter.CreatedBefore
+	}
+	if len(created) > 0 {
+		query["created_at"] = created
+	}
+	if pageToken != "" {
+		objectID, err := primitive.ObjectIDFromHex(pageToken)
+		if err == nil {
+			query["_id"] = bson.M{"$gt": objectID}
+		}
+	}
+
+	opts := options.Find().SetLimit(int64(pageSize)).SetSort(bson.M{"_id": 1})
+	cursor, err := r.executions.Find(ctx, query, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	var executions []*Execution
+	if err = cursor.All(ctx, &executions); err != nil {
+		return nil, "", err
+	}
+
+	var nextPageToken string
+	if len(executions) == int(pageSize) {
+		nextPageToken = executions[len(executions)-1].ID.Hex()
+	}
+
+	return executions, nextPageToken, nil
+}
+
+// CancelExecution marks an unfinished execution as cancelled, for
+// executions that are not running in this process
+func (r *ProtocolRepository) CancelExecution(ctx context.Context, executionID string) (*Execution, error) {
+	objectID, err := primitive.ObjectIDFromHex(executionID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"_id":    objectID,
+		"status": bson.M{"$nin": []string{StatusCompleted, StatusFailed, StatusCancelled}},
+	}
+	update := bson.M{"$set": bson.M{"status": StatusCancelled, "updated_at": time.Now()}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var execution Execution
+	err = r.executions.FindOneAndUpdate(ctx, filter, update, opts).Decode(&execution)
+	if err == mongo.ErrNoDocuments {
+		// Already finished, or unknown
+		return r.GetExecutionStatus(ctx, executionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &execution, nil
 }
 
 // GetExecutionStatus retrieves the status of a protocol execution
@@ -97,7 +489,7 @@ func (r *ProtocolRepository) GetExecutionStatus(ctx context.Context, executionID
 	}
 
 	var execution Execution
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&execution)
+	err = r.executions.FindOne(ctx, bson.M{"_id": objectID}).Decode(&execution)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,80 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		execution Execution
+		want      float64
+	}{
+		{"pending", Execution{Status: StatusPending}, 0},
+		{"steps not known yet", Execution{Status: StatusRunning}, 0},
+		{"none finished", Execution{Status: StatusRunning, TotalSteps: 4}, 0},
+		{"half finished", Execution{Status: StatusRunning, TotalSteps: 4, Steps: make([]StepResult, 2)}, 50},
+		{"failed midway", Execution{Status: StatusFailed, TotalSteps: 4, Steps: make([]StepResult, 1)}, 25},
+		{"more results than steps", Execution{Status: StatusRunning, TotalSteps: 2, Steps: make([]StepResult, 3)}, 100},
+		{"completed", Execution{Status: StatusCompleted}, 100},
+		{"completed from cache", Execution{Status: StatusCompleted, TotalSteps: 3}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.execution.Progress(); got != tt.want {
+				t.Errorf("Progress = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	started := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	finished := Execution{StartedAt: started, FinishedAt: started.Add(1500 * time.Millisecond)}
+	if got := finished.Duration(); got != 1500*time.Millisecond {
+		t.Errorf("finished Duration = %v, want 1.5s", got)
+	}
+
+	if got := (&Execution{}).Duration(); got != 0 {
+		t.Errorf("not started Duration = %v, want 0", got)
+	}
+
+	running := Execution{StartedAt: time.Now().Add(-time.Minute)}
+	if got := running.Duration(); got < time.Minute || got > 2*time.Minute {
+		t.Errorf("running Duration = %v, want about a minute", got)
+	}
+}
+
+func TestPinned(t *testing.T) {
+	e := &Execution{Models: []ModelPin{
+		{Ref: "reviewer@prod", ModelID: "64b000000000000000000001", Version: 3},
+		{Ref: "summarizer", ModelID: "64b000000000000000000002", Version: 1},
+	}}
+
+	pin, ok := e.Pinned("reviewer@prod")
+	if !ok || pin.String() != "64b000000000000000000001@v3" {
+		t.Errorf("Pinned(reviewer@prod) = %v, %v, want 64b000000000000000000001@v3", pin, ok)
+	}
+	if _, ok := e.Pinned("reviewer"); ok {
+		t.Error("Pinned(reviewer) found a pin of another reference")
+	}
+}
+
+func TestRestart(t *testing.T) {
+	e := &Execution{
+		Steps:    []StepResult{{ID: "draft"}},
+		Running:  []string{"review"},
+		Attempts: []Attempt{{StepID: "draft", Usage: Usage{PromptTokens: 10, Cost: 0.5}}},
+		Usage:    Usage{PromptTokens: 10, Cost: 0.5},
+	}
+	e.Restart()
+
+	if len(e.Steps) != 0 || len(e.Running) != 0 {
+		t.Errorf("after Restart steps = %v, running = %v, want none", e.Steps, e.Running)
+	}
+	if len(e.Attempts) != 1 || e.Usage.PromptTokens != 10 || e.Usage.Cost != 0.5 {
+		t.Errorf("after Restart attempts = %v, usage = %+v, want the earlier attempt and its usage", e.Attempts, e.Usage)
+	}
+}
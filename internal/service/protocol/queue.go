@@ -0,0 +1,174 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrLeaseLost is returned when a worker no longer holds an execution's
+// lease, because it expired and another worker claimed the execution or
+// because the execution was finished elsewhere
+var ErrLeaseLost = errors.New("execution lease lost")
+
+// QueueClass is the set of queued executions sharing a priority and
+// tenant. Executions are scheduled fairly between classes.
+type QueueClass struct {
+	Priority string `bson:"priority"`
+	Tenant   string `bson:"tenant"`
+}
+
+// QueueDepth counts the executions of a class waiting for and holding a
+// worker
+type QueueDepth struct {
+	QueueClass `bson:"_id"`
+	Pending    int64 `bson:"pending"`
+	Running    int64 `bson:"running"`
+}
+
+// ClaimFilter selects the executions to claim. Zero fields match all.
+type ClaimFilter struct {
+	ExecutionID string
+	QueueClass
+}
+
+// ClaimExecution leases a queued execution matching filter to owner until
+// the lease expires. Pending executions are claimed oldest first, as are
+// running executions whose worker stopped renewing its lease. It returns
+// nil when there is nothing to claim.
+func (r *ProtocolRepository) ClaimExecution(ctx context.Context, filter ClaimFilter, owner string, lease time.Duration) (*Execution, error) {
+	now := time.Now()
+	query := bson.M{"$or": []bson.M{
+		{"status": StatusPending},
+		{"status": StatusRunning, "lease_expires_at": bson.M{"$lt": now}},
+	}}
+	if filter.ExecutionID != "" {
+		objectID, err := primitive.ObjectIDFromHex(filter.ExecutionID)
+		if err != nil {
+			return nil, err
+		}
+		query["_id"] = objectID
+	}
+	if filter.Priority != "" {
+		query["priority"] = filter.Priority
+	}
+	if filter.Tenant != "" {
+		query["tenant"] = filter.Tenant
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":           StatusRunning,
+			"lease_owner":      owner,
+			"lease_expires_at": now.Add(lease),
+			"updated_at":       now,
+		},
+		"$inc": bson.M{"claims": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"_id": 1}).
+		SetReturnDocument(options.After)
+
+	var execution Execution
+	err := r.executions.FindOneAndUpdate(ctx, query, update, opts).Decode(&execution)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &execution, nil
+}
+
+// RenewLease extends owner's lease on a running execution. It returns
+// ErrLeaseLost if owner no longer holds it.
+func (r *ProtocolRepository) RenewLease(ctx context.Context, execution *Execution, lease time.Duration) error {
+	now := time.Now()
+	filter := bson.M{
+		"_id":         execution.ID,
+		"status":      StatusRunning,
+		"lease_owner": execution.LeaseOwner,
+	}
+	update := bson.M{"$set": bson.M{"lease_expires_at": now.Add(lease)}}
+
+	result, err := r.executions.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// ReleaseExecution returns a running execution to the queue, to be claimed
+// again from its first step. The release does not count as a claim, and
+// the attempts made so far stay counted with their usage. It returns
+// ErrLeaseLost if the execution has finished or is leased to another
+// worker.
+func (r *ProtocolRepository) ReleaseExecution(ctx context.Context, execution *Execution) error {
+	filter := bson.M{
+		"_id":         execution.ID,
+		"status":      StatusRunning,
+		"lease_owner": execution.LeaseOwner,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     StatusPending,
+			"steps":      []StepResult{},
+			"running":    []string{},
+			"updated_at": time.Now(),
+		},
+		"$unset": bson.M{"lease_owner": "", "lease_expires_at": ""},
+		"$inc":   bson.M{"claims": -1},
+	}
+
+	result, err := r.executions.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// QueueDepths counts the pending and running executions of every class,
+// optionally of a single tenant
+func (r *ProtocolRepository) QueueDepths(ctx context.Context, tenant string) ([]QueueDepth, error) {
+	match := bson.M{"status": bson.M{"$in": []string{StatusPending, StatusRunning}}}
+	if tenant != "" {
+		match["tenant"] = tenant
+	}
+	countStatus := func(status string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", status}}, 1, 0}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     bson.M{"priority": "$priority", "tenant": "$tenant"},
+			"pending": countStatus(StatusPending),
+			"running": countStatus(StatusRunning),
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.priority", Value: 1}, {Key: "_id.tenant", Value: 1}}}},
+	}
+
+	cursor, err := r.executions.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var depths []QueueDepth
+	if err := cursor.All(ctx, &depths); err != nil {
+		return nil, err
+	}
+	return depths, nil
+}
@@ -0,0 +1,142 @@
+package protocol
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testMongoURIEnv names the environment variable holding the URI of a
+// MongoDB server for the repository tests. They are skipped without it.
+const testMongoURIEnv = "MCP_TEST_MONGODB_URI"
+
+// testRepository returns a repository over a scratch database, dropped
+// when the test ends
+func testRepository(t *testing.T) *ProtocolRepository {
+	t.Helper()
+	uri := os.Getenv(testMongoURIEnv)
+	if uri == "" {
+		t.Skipf("%s not set", testMongoURIEnv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	db := client.Database("mcp_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		db.Drop(ctx)
+		client.Disconnect(ctx)
+	})
+	return NewProtocolRepository(db)
+}
+
+func TestProgressUpdateKeepsLease(t *testing.T) {
+	execution := &Execution{
+		ID:          primitive.NewObjectID(),
+		Status:      StatusRunning,
+		Result:      "partial",
+		LeaseOwner:  "worker-1",
+		LeaseExpiry: time.Now(),
+		Claims:      2,
+	}
+
+	update, err := progressUpdate(execution)
+	if err != nil {
+		t.Fatalf("progressUpdate: %v", err)
+	}
+	set, ok := update["$set"].(bson.M)
+	if !ok {
+		t.Fatalf("update = %v, want a $set", update)
+	}
+	for _, field := range []string{"_id", "lease_owner", "lease_expires_at", "claims"} {
+		if _, ok := set[field]; ok {
+			t.Errorf("update sets %q", field)
+		}
+	}
+	for field, want := range map[string]interface{}{"status": StatusRunning, "result": "partial"} {
+		if got := set[field]; got != want {
+			t.Errorf("update sets %q = %v, want %v", field, got, want)
+		}
+	}
+}
+
+func TestUpdateExecutionKeepsRenewedLease(t *testing.T) {
+	repo := testRepository(t)
+	ctx := context.Background()
+
+	created := &Execution{Status: StatusPending, Steps: []StepResult{}, Running: []string{}}
+	if err := repo.CreateExecution(ctx, created); err != nil {
+		t.Fatalf("CreateExecution: %v", err)
+	}
+	execution, err := repo.ClaimExecution(ctx, ClaimFilter{}, "worker-1", time.Minute)
+	if err != nil || execution == nil {
+		t.Fatalf("ClaimExecution = %v, %v", execution, err)
+	}
+	claimed := execution.LeaseExpiry
+
+	if err := repo.RenewLease(ctx, execution, time.Hour); err != nil {
+		t.Fatalf("RenewLease: %v", err)
+	}
+	execution.Result = "partial"
+	if err := repo.UpdateExecution(ctx, execution); err != nil {
+		t.Fatalf("UpdateExecution: %v", err)
+	}
+
+	stored, err := repo.GetExecutionStatus(ctx, execution.ID.Hex())
+	if err != nil {
+		t.Fatalf("GetExecutionStatus: %v", err)
+	}
+	if stored.Result != "partial" {
+		t.Errorf("stored result = %q, want %q", stored.Result, "partial")
+	}
+	if !stored.LeaseExpiry.After(claimed.Add(30 * time.Minute)) {
+		t.Errorf("stored lease expiry = %v, want the renewed expiry, not %v", stored.LeaseExpiry, claimed)
+	}
+	if stored.LeaseOwner != "worker-1" || stored.Claims != 1 {
+		t.Errorf("stored lease = %q claim %d, want worker-1 claim 1", stored.LeaseOwner, stored.Claims)
+	}
+}
+
+func TestReleaseExecutionKeepsAttempts(t *testing.T) {
+	repo := testRepository(t)
+	ctx := context.Background()
+
+	if err := repo.CreateExecution(ctx, &Execution{Steps: []StepResult{}, Running: []string{}}); err != nil {
+		t.Fatalf("CreateExecution: %v", err)
+	}
+	execution, err := repo.ClaimExecution(ctx, ClaimFilter{}, "worker-1", time.Minute)
+	if err != nil || execution == nil {
+		t.Fatalf("ClaimExecution = %v, %v", execution, err)
+	}
+	execution.Steps = []StepResult{{ID: "draft", Status: StatusCompleted}}
+	execution.Attempts = []Attempt{{StepID: "draft", Number: 1, Usage: Usage{PromptTokens: 10, Cost: 0.5}}}
+	execution.Usage = Usage{PromptTokens: 10, Cost: 0.5}
+	if err := repo.UpdateExecution(ctx, execution); err != nil {
+		t.Fatalf("UpdateExecution: %v", err)
+	}
+	if err := repo.ReleaseExecution(ctx, execution); err != nil {
+		t.Fatalf("ReleaseExecution: %v", err)
+	}
+
+	stored, err := repo.GetExecutionStatus(ctx, execution.ID.Hex())
+	if err != nil {
+		t.Fatalf("GetExecutionStatus: %v", err)
+	}
+	if stored.Status != StatusPending || len(stored.Steps) != 0 {
+		t.Errorf("released execution is %s with steps %v, want pending without steps", stored.Status, stored.Steps)
+	}
+	if len(stored.Attempts) != 1 || stored.Usage.PromptTokens != 10 || stored.Usage.Cost != 0.5 {
+		t.Errorf("released execution has attempts %v and usage %+v, want the attempt and its usage kept", stored.Attempts, stored.Usage)
+	}
+}
@@ -0,0 +1,172 @@
+package protocol
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// Step types
+const (
+	StepModel     = "model"     // calls the model with a prompt template
+	StepRender    = "render"    // renders the execution's context as a template
+	StepTransform = "transform" // renders a template over earlier outputs
+	StepBranch    = "branch"    // evaluates a condition and skips the untaken side
+	StepFanOut    = "fanout"    // calls the model once per item of a list
+	StepFanIn     = "fanin"     // joins the outputs of its dependencies
+	StepStore     = "store"     // stores a template result as data
+)
+
+// StepTypes lists the supported step types
+var StepTypes = []string{StepModel, StepRender, StepTransform, StepBranch, StepFanOut, StepFanIn, StepStore}
+
+// Step is a node of a protocol's pipeline. Steps run once all the steps they
+// depend on have finished, and read earlier outputs through templates.
+type Step struct {
+	ID        string            `bson:"id" json:"id"`
+	Type      string            `bson:"type" json:"type"`
+	DependsOn []string          `bson:"depends_on" json:"depends_on"`
+	Config    map[string]string `bson:"config" json:"config"`
+}
+
+// UnmarshalBSONValue decodes a step, accepting the plain strings stored
+// before steps were structured as model steps named after the string
+func (s *Step) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bsontype.String {
+		var name string
+		if err := bson.UnmarshalValue(t, data, &name); err != nil {
+			return err
+		}
+		*s = Step{ID: name, Type: StepModel}
+		return nil
+	}
+
+	type plain Step
+	return bson.UnmarshalValue(t, data, (*plain)(s))
+}
+
+// Targets returns the step IDs listed in a comma separated config value
+func (s *Step) Targets(key string) []string {
+	var ids []string
+	for _, id := range strings.Split(s.Config[key], ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// DefaultSteps is the pipeline of executions without steps of their own:
+// a single model call on the context followed by the input
+func DefaultSteps() []Step {
+	return []Step{{ID: "model", Type: StepModel}}
+}
+
+// ValidateSteps checks that steps form a DAG of known step types whose
+// dependencies and branch targets exist
+func ValidateSteps(steps []Step) error {
+	ids := make(map[string]bool, len(steps))
+	for i, step := range steps {
+		if step.ID == "" {
+			return fmt.Errorf("step %d has no id", i)
+		}
+		if ids[step.ID] {
+			return fmt.Errorf("duplicate step id %q", step.ID)
+		}
+		if !validStepType(step.Type) {
+			return fmt.Errorf("step %q has invalid type %q, must be one of %v", step.ID, step.Type, StepTypes)
+		}
+		ids[step.ID] = true
+	}
+
+	for _, step := range steps {
+		for _, dep := range step.DependsOn {
+			if !ids[dep] {
+				return fmt.Errorf("step %q depends on unknown step %q", step.ID, dep)
+			}
+		}
+		if step.Type == StepBranch {
+			if step.Config["condition"] == "" {
+				return fmt.Errorf("branch step %q has no condition", step.ID)
+			}
+			for _, target := range append(step.Targets("then"), step.Targets("else")...) {
+				if !ids[target] {
+					return fmt.Errorf("branch step %q targets unknown step %q", step.ID, target)
+				}
+				if !dependsOn(steps, target, step.ID) {
+					return fmt.Errorf("branch target %q must depend on %q", target, step.ID)
+				}
+			}
+		}
+	}
+
+	_, err := SortSteps(steps)
+	return err
+}
+
+// SortSteps orders steps so that every step comes after its dependencies
+func SortSteps(steps []Step) ([]Step, error) {
+	byID := make(map[string]Step, len(steps))
+	for _, step := range steps {
+		byID[step.ID] = step
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(steps))
+	sorted := make([]Step, 0, len(steps))
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("steps form a cycle through %q", id)
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		for _, dep := range byID[id].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		sorted = append(sorted, byID[id])
+		return nil
+	}
+
+	for _, step := range steps {
+		if err := visit(step.ID); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// dependsOn reports whether the step with the given id lists dep as a dependency
+func dependsOn(steps []Step, id, dep string) bool {
+	for _, step := range steps {
+		if step.ID != id {
+			continue
+		}
+		for _, d := range step.DependsOn {
+			if d == dep {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validStepType(t string) bool {
+	for _, valid := range StepTypes {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func step(id, typ string, deps ...string) Step {
+	return Step{ID: id, Type: typ, DependsOn: deps}
+}
+
+func branch(id, then, els string, deps ...string) Step {
+	s := step(id, StepBranch, deps...)
+	s.Config = map[string]string{"condition": "{{ .Input }}", "then": then, "else": els}
+	return s
+}
+
+func TestValidateSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []Step
+		err   string
+	}{
+		{"default", DefaultSteps(), ""},
+		{"empty", nil, ""},
+		{"chain", []Step{step("render", StepRender), step("model", StepModel, "render")}, ""},
+		{"diamond", []Step{
+			step("a", StepRender),
+			step("b", StepModel, "a"),
+			step("c", StepModel, "a"),
+			step("d", StepFanIn, "b", "c"),
+		}, ""},
+		{"branch", []Step{
+			branch("check", "yes", "no"),
+			step("yes", StepModel, "check"),
+			step("no", StepTransform, "check"),
+		}, ""},
+		{"no id", []Step{step("", StepModel)}, "step 0 has no id"},
+		{"duplicate id", []Step{step("a", StepModel), step("a", StepRender)}, `duplicate step id "a"`},
+		{"invalid type", []Step{step("a", "shell")}, `step "a" has invalid type "shell", must be one of [model render transform branch fanout fanin store]`},
+		{"unknown dependency", []Step{step("a", StepModel, "b")}, `step "a" depends on unknown step "b"`},
+		{"self dependency", []Step{step("a", StepModel, "a")}, `steps form a cycle through "a"`},
+		{"cycle", []Step{
+			step("a", StepModel, "c"),
+			step("b", StepModel, "a"),
+			step("c", StepModel, "b"),
+		}, `steps form a cycle through "a"`},
+		{"branch without condition", []Step{{ID: "check", Type: StepBranch}}, `branch step "check" has no condition`},
+		{"branch to unknown step", []Step{branch("check", "yes", "")}, `branch step "check" targets unknown step "yes"`},
+		{"branch target independent", []Step{
+			branch("check", "yes", ""),
+			step("yes", StepModel),
+		}, `branch target "yes" must depend on "check"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSteps(tt.steps)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("ValidateSteps = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("ValidateSteps = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSortSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []Step
+		want  []string
+	}{
+		{"sorted", []Step{step("a", StepRender), step("b", StepModel, "a")}, []string{"a", "b"}},
+		{"reversed", []Step{step("b", StepModel, "a"), step("a", StepRender)}, []string{"a", "b"}},
+		{"independent keep their order", []Step{step("b", StepModel), step("a", StepModel)}, []string{"b", "a"}},
+		{"diamond", []Step{
+			step("d", StepFanIn, "b", "c"),
+			step("c", StepModel, "a"),
+			step("b", StepModel, "a"),
+			step("a", StepRender),
+		}, []string{"a", "b", "c", "d"}},
+		{"shared dependency listed once", []Step{
+			step("x", StepModel, "a"),
+			step("y", StepModel, "a"),
+			step("a", StepRender),
+		}, []string{"a", "x", "y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := SortSteps(tt.steps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, s := range sorted {
+				got = append(got, s.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortSteps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SortSteps([]Step{step("a", StepModel, "b"), step("b", StepModel, "a")}); err == nil {
+		t.Error("SortSteps of a cycle = nil, want an error")
+	}
+}
+
+func TestStepTargets(t *testing.T) {
+	s := Step{Config: map[string]string{"then": " a, b ,,c ", "else": ""}}
+	if got, want := s.Targets("then"), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Targets(then) = %v, want %v", got, want)
+	}
+	if got := s.Targets("else"); got != nil {
+		t.Errorf("Targets(else) = %v, want none", got)
+	}
+}
+
+func TestStepUnmarshalLegacy(t *testing.T) {
+	data, err := bson.Marshal(bson.M{"steps": bson.A{
+		"summarize",
+		bson.M{"id": "store", "type": StepStore, "depends_on": bson.A{"summarize"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Steps []Step `bson:"steps"`
+	}
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Step{
+		{ID: "summarize", Type: StepModel},
+		{ID: "store", Type: StepStore, DependsOn: []string{"summarize"}},
+	}
+	if !reflect.DeepEqual(doc.Steps, want) {
+		t.Errorf("steps = %+v, want %+v", doc.Steps, want)
+	}
+}
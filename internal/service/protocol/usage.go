@@ -0,0 +1,122 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Usage report dimensions
+const (
+	GroupModel  = "model"
+	GroupTenant = "tenant"
+	GroupAPIKey = "api_key"
+	GroupDay    = "day"
+)
+
+// UsageGroups lists the dimensions usage can be grouped by
+var UsageGroups = []string{GroupModel, GroupTenant, GroupAPIKey, GroupDay}
+
+// UsageFilter selects the provider calls to report on. Zero fields match
+// all.
+type UsageFilter struct {
+	ModelID  string
+	Tenant   string
+	APIKeyID string
+	Start    time.Time
+	End      time.Time
+}
+
+// UsageRow is the usage of one group of provider calls. Only the fields
+// of the dimensions grouped by are set.
+type UsageRow struct {
+	Group struct {
+		ModelID  string `bson:"model"`
+		Tenant   string `bson:"tenant"`
+		APIKeyID string `bson:"api_key"`
+		Day      string `bson:"day"`
+	} `bson:"_id"`
+	Executions       int64   `bson:"executions"`
+	Calls            int64   `bson:"calls"`
+	PromptTokens     int64   `bson:"prompt_tokens"`
+	CompletionTokens int64   `bson:"completion_tokens"`
+	Cost             float64 `bson:"cost"`
+}
+
+// UsageReport aggregates the usage of provider calls by the given
+// dimensions. Calls are attributed to the model that served them and
+// to the UTC day they started.
+func (r *ProtocolRepository) UsageReport(ctx context.Context, groupBy []string, filter UsageFilter) ([]UsageRow, error) {
+	group := bson.M{}
+	for _, g := range groupBy {
+		switch g {
+		case GroupModel:
+			group["model"] = "$attempts.model_id"
+		case GroupTenant:
+			group["tenant"] = "$tenant"
+		case GroupAPIKey:
+			group["api_key"] = "$api_key_id"
+		case GroupDay:
+			group["day"] = bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$attempts.started_at"}}
+		default:
+			return nil, fmt.Errorf("invalid usage group %q, must be one of %v", g, UsageGroups)
+		}
+	}
+
+	match := bson.M{"attempts.0": bson.M{"$exists": true}}
+	if filter.Tenant != "" {
+		match["tenant"] = filter.Tenant
+	}
+	if filter.APIKeyID != "" {
+		match["api_key_id"] = filter.APIKeyID
+	}
+	if !filter.End.IsZero() {
+		match["created_at"] = bson.M{"$lt": filter.End}
+	}
+
+	callMatch := bson.M{}
+	if filter.ModelID != "" {
+		callMatch["attempts.model_id"] = filter.ModelID
+	}
+	started := bson.M{}
+	if !filter.Start.IsZero() {
+		started["$gte"] = filter.Start
+	}
+	if !filter.End.IsZero() {
+		started["$lt"] = filter.End
+	}
+	if len(started) > 0 {
+		callMatch["attempts.started_at"] = started
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$attempts"}},
+		{{Key: "$match", Value: callMatch}},
+		{{Key: "$group", Value: bson.M{
+			"_id":               group,
+			"executions":        bson.M{"$addToSet": "$_id"},
+			"calls":             bson.M{"$sum": 1},
+			"prompt_tokens":     bson.M{"$sum": "$attempts.usage.prompt_tokens"},
+			"completion_tokens": bson.M{"$sum": "$attempts.usage.completion_tokens"},
+			"cost":              bson.M{"$sum": "$attempts.usage.cost"},
+		}}},
+		{{Key: "$set", Value: bson.M{"executions": bson.M{"$size": "$executions"}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.day", Value: 1}, {Key: "cost", Value: -1}}}},
+	}
+
+	cursor, err := r.executions.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []UsageRow
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
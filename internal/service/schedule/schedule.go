@@ -0,0 +1,240 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Schedule runs a protocol execution on a cron expression or at a fixed
+// interval
+type Schedule struct {
+	ID              primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Name            string                 `bson:"name" json:"name"`
+	Cron            string                 `bson:"cron" json:"cron"`
+	IntervalSeconds int                    `bson:"interval_seconds" json:"interval_seconds"`
+	Paused          bool                   `bson:"paused" json:"paused"`
+	Request         Request                `bson:"request" json:"request"`
+	LastRunAt       time.Time              `bson:"last_run_at" json:"last_run_at"`
+	NextRunAt       time.Time              `bson:"next_run_at" json:"next_run_at"`
+	LastExecutionID string                 `bson:"last_execution_id" json:"last_execution_id"`
+	LastError       string                 `bson:"last_error" json:"last_error"`
+	CreatedAt       time.Time              `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time              `bson:"updated_at" json:"updated_at"`
+}
+
+// Request is the execution a schedule starts on every run
+type Request struct {
+	ProtocolID    string            `bson:"protocol_id" json:"protocol_id"`
+	Type          string            `bson:"type" json:"type"`
+	ModelID       string            `bson:"model_id" json:"model_id"`
+	ContextID     string            `bson:"context_id" json:"context_id"`
+	Input         string            `bson:"input" json:"input"`
+	Parameters    map[string]string `bson:"parameters" json:"parameters"`
+	AllowUnlinked bool              `bson:"allow_unlinked" json:"allow_unlinked"`
+	Priority      string            `bson:"priority" json:"priority"`
+	Tenant        string            `bson:"tenant" json:"tenant"`
+}
+
+// Validate checks that the schedule has exactly one valid trigger
+func (s *Schedule) Validate() error {
+	if s.Name == "" {
+		return errors.New("schedule name is required")
+	}
+	if (s.Cron == "") == (s.IntervalSeconds <= 0) {
+		return errors.New("schedule needs either a cron expression or an interval")
+	}
+	if s.Cron != "" {
+		if _, err := cron.ParseStandard(s.Cron); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Next returns the first run time of the schedule after t
+func (s *Schedule) Next(t time.Time) (time.Time, error) {
+	if s.Cron == "" {
+		return t.Add(time.Duration(s.IntervalSeconds) * time.Second), nil
+	}
+
+	spec, err := cron.ParseStandard(s.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return spec.Next(t), nil
+}
+
+// ScheduleRepository handles database operations for schedules
+type ScheduleRepository struct {
+	collection *mongo.Collection
+}
+
+// NewScheduleRepository creates a new ScheduleRepository
+func NewScheduleRepository(db *mongo.Database) *ScheduleRepository {
+	return &ScheduleRepository{
+		collection: db.Collection("schedules"),
+	}
+}
+
+// Create stores a new schedule and sets its first run time
+func (r *ScheduleRepository) Create(ctx context.Context, schedule *Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	next, err := schedule.Next(time.Now())
+	if err != nil {
+		return err
+	}
+	schedule.NextRunAt = next
+	schedule.CreatedAt = time.Now()
+	schedule.UpdatedAt = time.Now()
+
+	result, err := r.collection.InsertOne(ctx, schedule)
+	if err != nil {
+		return err
+	}
+	schedule.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Get retrieves a schedule by ID
+func (r *ScheduleRepository) Get(ctx context.Context, id string) (*Schedule, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule Schedule
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// List retrieves all schedules with pagination
+func (r *ScheduleRepository) List(ctx context.Context, pageSize int32, pageToken string) ([]*Schedule, string, error) {
+	filter := bson.M{}
+	if pageToken != "" {
+		objectID, err := primitive.ObjectIDFromHex(pageToken)
+		if err == nil {
+			filter["_id"] = bson.M{"$gt": objectID}
+		}
+	}
+
+	opts := options.Find().SetLimit(int64(pageSize))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	var schedules []*Schedule
+	if err = cursor.All(ctx, &schedules); err != nil {
+		return nil, "", err
+	}
+
+	var nextPageToken string
+	if len(schedules) == int(pageSize) {
+		nextPageToken = schedules[len(schedules)-1].ID.Hex()
+	}
+
+	return schedules, nextPageToken, nil
+}
+
+// SetPaused pauses or resumes a schedule. A resumed schedule next runs at
+// its first run time from now rather than catching up on missed runs.
+func (r *ScheduleRepository) SetPaused(ctx context.Context, id string, paused bool) (*Schedule, error) {
+	schedule, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	set := bson.M{"paused": paused, "updated_at": time.Now()}
+	if !paused && schedule.Paused {
+		next, err := schedule.Next(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		set["next_run_at"] = next
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": schedule.ID}, bson.M{"$set": set}, opts).Decode(schedule)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Delete removes a schedule by ID
+func (r *ScheduleRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
+
+// Due retrieves the active schedules whose next run time has passed
+func (r *ScheduleRepository) Due(ctx context.Context, now time.Time) ([]*Schedule, error) {
+	filter := bson.M{
+		"paused":      false,
+		"next_run_at": bson.M{"$lte": now},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.M{"next_run_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var schedules []*Schedule
+	if err = cursor.All(ctx, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// Advance moves a due schedule to its next run time. It reports false if
+// the schedule was advanced, paused or deleted concurrently, in which case
+// this run must not fire.
+func (r *ScheduleRepository) Advance(ctx context.Context, schedule *Schedule, ranAt, next time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":         schedule.ID,
+		"paused":      false,
+		"next_run_at": schedule.NextRunAt,
+	}
+	update := bson.M{"$set": bson.M{
+		"last_run_at": ranAt,
+		"next_run_at": next,
+		"updated_at":  time.Now(),
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// RecordRun stores the outcome of starting a schedule's execution
+func (r *ScheduleRepository) RecordRun(ctx context.Context, id primitive.ObjectID, executionID, errMsg string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{
+		"last_execution_id": executionID,
+		"last_error":        errMsg,
+		"updated_at":        time.Now(),
+	}})
+	return err
+}
@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		err      string
+	}{
+		{"cron", Schedule{Name: "nightly", Cron: "0 2 * * *"}, ""},
+		{"cron descriptor", Schedule{Name: "hourly", Cron: "@hourly"}, ""},
+		{"interval", Schedule{Name: "poll", IntervalSeconds: 60}, ""},
+		{"no name", Schedule{Cron: "0 2 * * *"}, "schedule name is required"},
+		{"no trigger", Schedule{Name: "none"}, "schedule needs either a cron expression or an interval"},
+		{"negative interval", Schedule{Name: "none", IntervalSeconds: -1}, "schedule needs either a cron expression or an interval"},
+		{"both triggers", Schedule{Name: "both", Cron: "0 2 * * *", IntervalSeconds: 60}, "schedule needs either a cron expression or an interval"},
+		{"invalid cron", Schedule{Name: "bad", Cron: "every night"}, "expected exactly 5 fields, found 2: [every night]"},
+		{"cron with seconds", Schedule{Name: "bad", Cron: "0 0 2 * * *"}, "expected exactly 5 fields, found 6: [0 0 2 * * *]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("Validate = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		from     string
+		want     string
+	}{
+		{"interval", Schedule{IntervalSeconds: 90}, "2024-03-01T10:00:00Z", "2024-03-01T10:01:30Z"},
+		{"daily later today", Schedule{Cron: "0 2 * * *"}, "2024-03-01T01:59:59Z", "2024-03-01T02:00:00Z"},
+		{"daily tomorrow", Schedule{Cron: "0 2 * * *"}, "2024-03-01T02:00:00Z", "2024-03-02T02:00:00Z"},
+		{"every 15 minutes", Schedule{Cron: "*/15 * * * *"}, "2024-03-01T10:07:00Z", "2024-03-01T10:15:00Z"},
+		{"weekdays skip the weekend", Schedule{Cron: "0 9 * * 1-5"}, "2024-03-01T09:00:00Z", "2024-03-04T09:00:00Z"},
+		{"leap day", Schedule{Cron: "0 0 29 2 *"}, "2024-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"descriptor", Schedule{Cron: "@hourly"}, "2024-03-01T10:30:00Z", "2024-03-01T11:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.schedule.Next(at(tt.from))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := at(tt.want); !got.Equal(want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got.Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+
+	if _, err := (&Schedule{Cron: "every night"}).Next(time.Now()); err == nil {
+		t.Error("Next with an invalid cron expression = nil error, want one")
+	}
+}
@@ -0,0 +1,93 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeyEnv is the environment variable holding the base64 encoded
+// encryption key, taking precedence over a key file
+const KeyEnv = "MCP_SECRETS_KEY"
+
+// KeySize is the size of the AES-256 encryption key in bytes
+const KeySize = 32
+
+// Cipher encrypts secrets with AES-GCM
+type Cipher struct {
+	aead  cipher.AEAD
+	keyID string
+}
+
+// NewCipher creates a Cipher from a 32 byte key
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("secrets key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(key)
+	return &Cipher{aead: aead, keyID: hex.EncodeToString(sum[:4])}, nil
+}
+
+// LoadCipher creates a Cipher from the base64 key in the KeyEnv
+// environment variable, or else in keyFile. It returns nil when neither is
+// set.
+func LoadCipher(keyFile string) (*Cipher, error) {
+	encoded := os.Getenv(KeyEnv)
+	source := KeyEnv
+	if encoded == "" && keyFile != "" {
+		b, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading secrets key: %v", err)
+		}
+		encoded = string(b)
+		source = keyFile
+	}
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("secrets key in %s is not base64: %v", source, err)
+	}
+	return NewCipher(key)
+}
+
+// KeyID identifies the key without revealing it, so that secrets
+// encrypted with another key are told apart
+func (c *Cipher) KeyID() string {
+	return c.keyID
+}
+
+// Seal encrypts plaintext with a random nonce. The additional data is
+// authenticated but not encrypted, binding the ciphertext to it.
+func (c *Cipher) Seal(plaintext, additional []byte) (nonce, ciphertext []byte, err error) {
+	nonce = make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, c.aead.Seal(nil, nonce, plaintext, additional), nil
+}
+
+// Open decrypts and authenticates a ciphertext made by Seal
+func (c *Cipher) Open(nonce, ciphertext, additional []byte) ([]byte, error) {
+	if len(nonce) != c.aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce size %d", len(nonce))
+	}
+	return c.aead.Open(nil, nonce, ciphertext, additional)
+}
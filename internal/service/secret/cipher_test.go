@@ -0,0 +1,122 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c, err := NewCipher(testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewCipher(testKey(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte("sk-test")
+	nonce, ciphertext, err := c.Seal(plaintext, []byte("openai"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Fatal("ciphertext contains the plaintext")
+	}
+
+	tests := []struct {
+		name       string
+		cipher     *Cipher
+		nonce      []byte
+		ciphertext []byte
+		additional string
+		ok         bool
+	}{
+		{"same key and name", c, nonce, ciphertext, "openai", true},
+		{"other name", c, nonce, ciphertext, "anthropic", false},
+		{"other key", other, nonce, ciphertext, "openai", false},
+		{"tampered", c, nonce, append([]byte{ciphertext[0] ^ 1}, ciphertext[1:]...), "openai", false},
+		{"short nonce", c, nonce[:4], ciphertext, "openai", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cipher.Open(tt.nonce, tt.ciphertext, []byte(tt.additional))
+			if !tt.ok {
+				if err == nil {
+					t.Fatalf("Open succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Fatalf("Open = %q, want %q", got, plaintext)
+			}
+		})
+	}
+}
+
+func TestCipherNonces(t *testing.T) {
+	c, err := NewCipher(testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce1, ciphertext1, _ := c.Seal([]byte("value"), nil)
+	nonce2, ciphertext2, _ := c.Seal([]byte("value"), nil)
+	if bytes.Equal(nonce1, nonce2) || bytes.Equal(ciphertext1, ciphertext2) {
+		t.Fatal("sealing twice reused the nonce")
+	}
+}
+
+func TestNewCipher(t *testing.T) {
+	if _, err := NewCipher(make([]byte, 16)); err == nil {
+		t.Error("NewCipher accepted a 16 byte key")
+	}
+
+	a, _ := NewCipher(testKey(1))
+	b, _ := NewCipher(testKey(1))
+	c, _ := NewCipher(testKey(2))
+	if a.KeyID() != b.KeyID() {
+		t.Error("the same key has different IDs")
+	}
+	if a.KeyID() == c.KeyID() {
+		t.Error("different keys have the same ID")
+	}
+}
+
+func TestLoadCipher(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(testKey(3))
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte(encoded+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := NewCipher(testKey(3))
+
+	t.Setenv(KeyEnv, "")
+	if c, err := LoadCipher(""); c != nil || err != nil {
+		t.Fatalf("LoadCipher without a key = %v, %v, want nil, nil", c, err)
+	}
+	c, err := LoadCipher(keyFile)
+	if err != nil || c.KeyID() != want.KeyID() {
+		t.Fatalf("LoadCipher from file = %v, %v", c, err)
+	}
+
+	t.Setenv(KeyEnv, base64.StdEncoding.EncodeToString(testKey(4)))
+	c, err = LoadCipher(keyFile)
+	if err != nil || c.KeyID() == want.KeyID() {
+		t.Fatal("the environment variable does not take precedence over the file")
+	}
+
+	t.Setenv(KeyEnv, "not base64!")
+	if _, err := LoadCipher(""); err == nil {
+		t.Fatal("LoadCipher accepted an invalid key")
+	}
+}
@@ -0,0 +1,182 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotConfigured is returned when no encryption key was configured
+var ErrNotConfigured = errors.New("secrets are not configured: set an encryption key")
+
+var (
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+	hostPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]{0,251}[a-z0-9])?$`)
+)
+
+// Secret is a named credential, such as a provider API key, stored
+// encrypted. Its value is only ever decrypted by Reveal.
+type Secret struct {
+	Name        string    `bson:"_id" json:"name"`
+	Description string    `bson:"description" json:"description"`
+	Hosts       []string  `bson:"hosts" json:"hosts"` // where a model's base_url may send the value, besides the providers' own endpoints
+	KeyID       string    `bson:"key_id" json:"key_id"`
+	Nonce       []byte    `bson:"nonce" json:"-"`
+	Ciphertext  []byte    `bson:"ciphertext" json:"-"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// SecretRepository handles database operations for secrets
+type SecretRepository struct {
+	collection *mongo.Collection
+	cipher     *Cipher
+}
+
+// NewSecretRepository creates a new SecretRepository encrypting with
+// cipher. Without a cipher secrets can be listed and deleted but not
+// stored or revealed.
+func NewSecretRepository(db *mongo.Database, cipher *Cipher) *SecretRepository {
+	return &SecretRepository{
+		collection: db.Collection("secrets"),
+		cipher:     cipher,
+	}
+}
+
+// AllowsHost reports whether the value may be sent to host. The empty
+// host stands for the provider's own endpoint.
+func (s *Secret) AllowsHost(host string) bool {
+	if host == "" {
+		return true
+	}
+	for _, allowed := range s.Hosts {
+		if strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Put stores the value of the named secret, replacing any previous one,
+// and the hosts besides the providers' own endpoints it may be sent to
+func (r *SecretRepository) Put(ctx context.Context, name, value, description string, hosts []string) (*Secret, error) {
+	if r.cipher == nil {
+		return nil, ErrNotConfigured
+	}
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid secret name %q: use letters, digits, '_', '.' and '-'", name)
+	}
+	if value == "" {
+		return nil, fmt.Errorf("secret %s has an empty value", name)
+	}
+	normalized := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if !hostPattern.MatchString(host) {
+			return nil, fmt.Errorf("invalid host %q for secret %s: use a host name without scheme or port", host, name)
+		}
+		normalized = append(normalized, host)
+	}
+
+	nonce, ciphertext, err := r.cipher.Seal([]byte(value), []byte(name))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"description": description,
+			"hosts":       normalized,
+			"key_id":      r.cipher.KeyID(),
+			"nonce":       nonce,
+			"ciphertext":  ciphertext,
+			"updated_at":  now,
+		},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var secret Secret
+	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": name}, update, opts).Decode(&secret)
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// Get retrieves a secret by name, without decrypting it
+func (r *SecretRepository) Get(ctx context.Context, name string) (*Secret, error) {
+	var secret Secret
+	err := r.collection.FindOne(ctx, bson.M{"_id": name}).Decode(&secret)
+	if err == mongo.ErrNoDocuments {
+		return nil, fmt.Errorf("secret %s not found", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// List retrieves all secrets by name, without their values
+func (r *SecretRepository) List(ctx context.Context) ([]*Secret, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"nonce": 0, "ciphertext": 0})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var secrets []*Secret
+	if err := cursor.All(ctx, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// Delete removes the named secret
+func (r *SecretRepository) Delete(ctx context.Context, name string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": name})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("secret %s not found", name)
+	}
+	return nil
+}
+
+// Reveal decrypts the value of the named secret, to be sent to host (the
+// provider's own endpoint when empty). It is meant for credentials about
+// to be sent to a provider: the value must not be returned to clients or
+// logged.
+func (r *SecretRepository) Reveal(ctx context.Context, name, host string) (string, error) {
+	if r.cipher == nil {
+		return "", ErrNotConfigured
+	}
+	secret, err := r.Get(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if !secret.AllowsHost(host) {
+		return "", fmt.Errorf("secret %s may not be sent to %s", name, host)
+	}
+	if secret.KeyID != r.cipher.KeyID() {
+		return "", fmt.Errorf("secret %s was encrypted with key %s, not the configured key %s", name, secret.KeyID, r.cipher.KeyID())
+	}
+
+	value, err := r.cipher.Open(secret.Nonce, secret.Ciphertext, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("decrypting secret %s: %v", name, err)
+	}
+	return string(value), nil
+}
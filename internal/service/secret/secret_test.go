@@ -0,0 +1,45 @@
+package secret
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestAllowsHost(t *testing.T) {
+	s := &Secret{Name: "gateway", Hosts: []string{"llm.internal.example.com"}}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"", true},
+		{"llm.internal.example.com", true},
+		{"LLM.Internal.Example.com", true},
+		{"attacker.example", false},
+		{"internal.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := s.AllowsHost(tt.host); got != tt.want {
+			t.Errorf("AllowsHost(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+	if (&Secret{}).AllowsHost("api.example.com") {
+		t.Error("secret without hosts allows api.example.com")
+	}
+}
+
+func TestPutRejectsInvalidHosts(t *testing.T) {
+	c, err := NewCipher(testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &SecretRepository{cipher: c}
+
+	for _, host := range []string{"https://llm.example.com", "llm.example.com:8443", "llm.example.com/v1", "", "-llm.example.com"} {
+		_, err := r.Put(context.Background(), "gateway", "sk-test", "", []string{host})
+		if err == nil || !strings.Contains(err.Error(), "invalid host") {
+			t.Errorf("Put with host %q: error = %v, want an invalid host error", host, err)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package tokenizer
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// BPE is a byte pair encoding tokenizer using tiktoken rank files and the
+// cl100k_base pre-tokenization rules
+type BPE struct {
+	name  string
+	ranks map[string]int
+}
+
+// NewBPE creates a BPE tokenizer from mergeable ranks
+func NewBPE(name string, ranks map[string]int) *BPE {
+	return &BPE{
+		name:  name,
+		ranks: ranks,
+	}
+}
+
+// LoadBPE reads a tiktoken rank file, where each line holds a base64
+// encoded token and its rank
+func LoadBPE(name, path string) (*BPE, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: malformed rank entry", path, line)
+		}
+
+		token, err := base64.StdEncoding.DecodeString(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		rank, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		ranks[string(token)] = rank
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewBPE(name, ranks), nil
+}
+
+// Name implements the Tokenizer interface
+func (b *BPE) Name() string {
+	return b.name
+}
+
+// Count implements the Tokenizer interface
+func (b *BPE) Count(text string) int {
+	count := 0
+	for _, piece := range splitCL100K(text) {
+		count += len(b.encodePiece(piece))
+	}
+	return count
+}
+
+// Truncate implements the Tokenizer interface
+func (b *BPE) Truncate(text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	for _, piece := range splitCL100K(text) {
+		for _, token := range b.encodePiece(piece) {
+			if maxTokens == 0 {
+				return trimPartialRune(out.String())
+			}
+			out.WriteString(token)
+			maxTokens--
+		}
+	}
+	return out.String()
+}
+
+// encodePiece applies the byte pair merges to a single pre-token
+func (b *BPE) encodePiece(piece string) []string {
+	if _, ok := b.ranks[piece]; ok {
+		return []string{piece}
+	}
+
+	// bounds holds the start offset of every part, plus the end of the piece
+	bounds := make([]int, len(piece)+1)
+	for i := range bounds {
+		bounds[i] = i
+	}
+
+	for len(bounds) > 2 {
+		best, bestRank := -1, math.MaxInt
+		for i := 0; i+2 < len(bounds); i++ {
+			if rank, ok := b.ranks[piece[bounds[i]:bounds[i+2]]]; ok && rank < bestRank {
+				best, bestRank = i, rank
+			}
+		}
+		if best < 0 {
+			break
+		}
+		bounds = append(bounds[:best+1], bounds[best+2:]...)
+	}
+
+	tokens := make([]string, 0, len(bounds)-1)
+	for i := 0; i+1 < len(bounds); i++ {
+		tokens = append(tokens, piece[bounds[i]:bounds[i+1]])
+	}
+	return tokens
+}
+
+// splitCL100K splits text the way the cl100k_base pattern does:
+//
+//	(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}|
+//	 ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+
+//
+// Go's regexp package has no lookahead, so the alternatives are matched by hand.
+func splitCL100K(text string) []string {
+	var pieces []string
+	for len(text) > 0 {
+		n := matchCL100K(text)
+		pieces = append(pieces, text[:n])
+		text = text[n:]
+	}
+	return pieces
+}
+
+// matchCL100K returns the length of the pre-token at the start of text
+func matchCL100K(text string) int {
+	r, size := utf8.DecodeRuneInString(text)
+	next, nextSize := utf8.DecodeRuneInString(text[size:])
+	hasNext := size < len(text)
+
+	// Contractions
+	if r == '\'' {
+		lower := strings.ToLower(text[size:min(len(text), size+2)])
+		for _, suffix := range []string{"re", "ve", "ll", "s", "t", "m", "d"} {
+			if strings.HasPrefix(lower, suffix) {
+				return size + len(suffix)
+			}
+		}
+	}
+
+	// Words with an optional leading non-letter
+	if unicode.IsLetter(r) {
+		return size + spanOf(text[size:], unicode.IsLetter)
+	}
+	if hasNext && unicode.IsLetter(next) && r != '\r' && r != '\n' && !unicode.IsNumber(r) {
+		return size + nextSize + spanOf(text[size+nextSize:], unicode.IsLetter)
+	}
+
+	// Up to three digits
+	if unicode.IsNumber(r) {
+		n := size
+		for i := 1; i < 3 && n < len(text); i++ {
+			d, dsize := utf8.DecodeRuneInString(text[n:])
+			if !unicode.IsNumber(d) {
+				break
+			}
+			n += dsize
+		}
+		return n
+	}
+
+	// Punctuation with an optional leading space and trailing newlines
+	start := 0
+	if r == ' ' && hasNext && isPunct(next) {
+		start = size
+	}
+	if n := spanOf(text[start:], isPunct); n > 0 {
+		n += start
+		return n + spanOf(text[n:], isNewline)
+	}
+
+	// Whitespace
+	ws := spanOf(text, unicode.IsSpace)
+	if lastNewline := strings.LastIndexAny(text[:ws], "\r\n"); lastNewline >= 0 {
+		return lastNewline + 1
+	}
+	if ws == len(text) {
+		return ws
+	}
+	if _, lastSize := utf8.DecodeLastRuneInString(text[:ws]); ws > lastSize {
+		return ws - lastSize
+	}
+	if ws > 0 {
+		return ws
+	}
+
+	return size
+}
+
+// spanOf returns the byte length of the prefix of s whose runes satisfy f
+func spanOf(s string, f func(rune) bool) int {
+	for i, r := range s {
+		if !f(r) {
+			return i
+		}
+	}
+	return len(s)
+}
+
+func isPunct(r rune) bool {
+	return !unicode.IsSpace(r) && !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+func isNewline(r rune) bool {
+	return r == '\r' || r == '\n'
+}
+
+// trimPartialRune drops a trailing incomplete UTF-8 sequence left behind
+// when truncation splits a multi-byte character across tokens
+func trimPartialRune(s string) string {
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
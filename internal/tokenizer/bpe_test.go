@@ -0,0 +1,124 @@
+package tokenizer
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSplitCL100K(t *testing.T) {
+	// Expected pieces as produced by tiktoken's cl100k_base pattern
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"Hello world", []string{"Hello", " world"}},
+		{"hello, world!", []string{"hello", ",", " world", "!"}},
+		{"I'm here", []string{"I", "'m", " here"}},
+		{"HELLO'S", []string{"HELLO", "'S"}},
+		{"they'll've", []string{"they", "'ll", "'ve"}},
+		{"12345", []string{"123", "45"}},
+		{"$100", []string{"$", "100"}},
+		{"a  b", []string{"a", " ", " b"}},
+		{"  hello", []string{" ", " hello"}},
+		{"trailing  ", []string{"trailing", "  "}},
+		{"line\n\nnext", []string{"line", "\n\n", "next"}},
+		{"end.\n", []string{"end", ".\n"}},
+		{"x \n y", []string{"x", " \n", " y"}},
+		{" ...\n", []string{" ...\n"}},
+		{"你好 世界", []string{"你好", " 世界"}},
+		{"(foo)", []string{"(foo", ")"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := splitCL100K(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitCL100K(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// ranks builds a rank table from tokens listed by increasing rank
+func ranks(tokens ...string) map[string]int {
+	r := make(map[string]int, len(tokens))
+	for i, token := range tokens {
+		r[token] = i
+	}
+	return r
+}
+
+func TestEncodePiece(t *testing.T) {
+	tests := []struct {
+		name  string
+		ranks map[string]int
+		piece string
+		want  []string
+	}{
+		{"whole piece ranked", ranks("hello"), "hello", []string{"hello"}},
+		{"no merges", ranks(), "abc", []string{"a", "b", "c"}},
+		{"single merge", ranks("ab"), "abc", []string{"ab", "c"}},
+		{"lowest rank merges first", ranks("bc", "ab"), "abc", []string{"a", "bc"}},
+		{"merges build on merges", ranks("ab", "abc", "abcd", "cd"), "abcd", []string{"abcd"}},
+		{"repeated pairs", ranks("aa"), "aaaaa", []string{"aa", "aa", "a"}},
+		{"multibyte", ranks("\xc3", "é"), "é", []string{"é"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBPE("test", tt.ranks)
+			if got := b.encodePiece(tt.piece); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("encodePiece(%q) = %q, want %q", tt.piece, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBPETruncate(t *testing.T) {
+	b := NewBPE("test", ranks("he", "llo", "hello", " wo", "rld", " world"))
+
+	tests := []struct {
+		text      string
+		maxTokens int
+		want      string
+	}{
+		{"hello world", 0, ""},
+		{"hello world", 1, "hello"},
+		{"hello world", 2, "hello world"},
+		{"hello world", 5, "hello world"},
+		// "é" is two single byte tokens: the partial character is dropped
+		{"hé", 2, "h"},
+	}
+	for _, tt := range tests {
+		if got := b.Truncate(tt.text, tt.maxTokens); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.text, tt.maxTokens, got, tt.want)
+		}
+	}
+}
+
+func TestCL100KCounts(t *testing.T) {
+	path := os.Getenv(RanksFileEnv)
+	if path == "" {
+		t.Skipf("%s is not set", RanksFileEnv)
+	}
+	b, err := LoadBPE(CL100KBase, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Counts given by tiktoken for cl100k_base
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello world", 2},
+		{"Hello, world!", 4},
+		{"tiktoken is great!", 6},
+		{"1234567", 3},
+	}
+	for _, tt := range tests {
+		if got := b.Count(tt.text); got != tt.want {
+			t.Errorf("Count(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
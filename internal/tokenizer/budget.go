@@ -0,0 +1,45 @@
+package tokenizer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Policies applied when a prompt does not fit in a model's context window
+const (
+	PolicyFail     = "fail"
+	PolicyTruncate = "truncate"
+)
+
+// ErrContextWindowExceeded is returned when a prompt does not fit and the
+// policy does not allow truncation
+var ErrContextWindowExceeded = errors.New("context window exceeded")
+
+// Fit checks that content plus input fits in budget tokens. Under the
+// truncate policy the content is shortened to make room for the input;
+// the input itself is never truncated. A budget of zero means unlimited.
+func Fit(t Tokenizer, content, input string, budget int, policy string) (string, error) {
+	if budget <= 0 {
+		return content, nil
+	}
+
+	contentTokens := t.Count(content)
+	inputTokens := t.Count(input)
+	if contentTokens+inputTokens <= budget {
+		return content, nil
+	}
+
+	switch policy {
+	case "", PolicyFail:
+		return "", fmt.Errorf("%w: %d context + %d input tokens, budget is %d",
+			ErrContextWindowExceeded, contentTokens, inputTokens, budget)
+	case PolicyTruncate:
+		if inputTokens >= budget {
+			return "", fmt.Errorf("%w: input alone needs %d tokens, budget is %d",
+				ErrContextWindowExceeded, inputTokens, budget)
+		}
+		return t.Truncate(content, budget-inputTokens), nil
+	default:
+		return "", fmt.Errorf("unknown truncation policy: %s", policy)
+	}
+}
@@ -0,0 +1,71 @@
+package tokenizer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	// The estimator counts a token per 4 characters
+	est := NewEstimator(4)
+	content := strings.Repeat("c", 40) // 10 tokens
+	input := strings.Repeat("i", 20)   // 5 tokens
+
+	tests := []struct {
+		name   string
+		budget int
+		policy string
+		want   string
+		err    error
+		errMsg string
+	}{
+		{"unlimited", 0, PolicyFail, content, nil, ""},
+		{"fits", 15, PolicyFail, content, nil, ""},
+		{"fits without policy", 15, "", content, nil, ""},
+		{"fail", 14, PolicyFail, "", ErrContextWindowExceeded, "10 context + 5 input tokens, budget is 14"},
+		{"fail by default", 14, "", "", ErrContextWindowExceeded, ""},
+		{"truncate", 12, PolicyTruncate, strings.Repeat("c", 28), nil, ""},
+		{"truncate to nothing but the input", 6, PolicyTruncate, "cccc", nil, ""},
+		{"input alone too long", 5, PolicyTruncate, "", ErrContextWindowExceeded, "input alone needs 5 tokens, budget is 5"},
+		{"unknown policy", 14, "drop", "", nil, "unknown truncation policy: drop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Fit(est, content, input, tt.budget, tt.policy)
+			if tt.err == nil && tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("Fit = %v, want nil", err)
+				}
+				if got != tt.want {
+					t.Errorf("Fit = %q, want %q", got, tt.want)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Fit = %q, want an error", got)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Fit error = %v, want %v", err, tt.err)
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Fit error = %q, want it to contain %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestFitBPE(t *testing.T) {
+	b := NewBPE("test", ranks("he", "llo", "hello", " wo", "rld", " world"))
+
+	got, err := Fit(b, "hello world hello", "hello", 3, PolicyTruncate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "hello world"; got != want {
+		t.Errorf("Fit = %q, want %q", got, want)
+	}
+	if _, err := Fit(b, "hello world hello", "hello", 3, PolicyFail); !errors.Is(err, ErrContextWindowExceeded) {
+		t.Errorf("Fit error = %v, want %v", err, ErrContextWindowExceeded)
+	}
+}
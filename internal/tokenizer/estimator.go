@@ -0,0 +1,43 @@
+package tokenizer
+
+import "unicode/utf8"
+
+// Estimator approximates token counts from the number of characters
+type Estimator struct {
+	charsPerToken int
+}
+
+// NewEstimator creates an Estimator assuming charsPerToken characters per token
+func NewEstimator(charsPerToken int) *Estimator {
+	if charsPerToken <= 0 {
+		charsPerToken = 4
+	}
+	return &Estimator{charsPerToken: charsPerToken}
+}
+
+// Name implements the Tokenizer interface
+func (e *Estimator) Name() string {
+	return Estimate
+}
+
+// Count implements the Tokenizer interface
+func (e *Estimator) Count(text string) int {
+	n := utf8.RuneCountInString(text)
+	return (n + e.charsPerToken - 1) / e.charsPerToken
+}
+
+// Truncate implements the Tokenizer interface
+func (e *Estimator) Truncate(text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+
+	limit := maxTokens * e.charsPerToken
+	for i := range text {
+		if limit == 0 {
+			return text[:i]
+		}
+		limit--
+	}
+	return text
+}
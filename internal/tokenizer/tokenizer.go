@@ -0,0 +1,89 @@
+package tokenizer
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// Tokenizer names understood by the registry
+const (
+	CL100KBase = "cl100k_base"
+	Estimate   = "estimate"
+)
+
+// RanksFileEnv names the environment variable pointing at a cl100k_base
+// rank file in tiktoken format
+const RanksFileEnv = "MCP_CL100K_RANKS_FILE"
+
+// Tokenizer counts and truncates text in model tokens
+type Tokenizer interface {
+	Name() string
+	Count(text string) int
+	Truncate(text string, maxTokens int) string
+}
+
+var (
+	mu         sync.RWMutex
+	tokenizers = map[string]Tokenizer{
+		Estimate: NewEstimator(4),
+	}
+)
+
+// Register makes a tokenizer available under the given name
+func Register(name string, t Tokenizer) {
+	mu.Lock()
+	defer mu.Unlock()
+	tokenizers[name] = t
+}
+
+// Get returns the tokenizer registered under name, falling back to the
+// character based estimator when it is unknown
+func Get(name string) Tokenizer {
+	mu.RLock()
+	defer mu.RUnlock()
+	if t, ok := tokenizers[name]; ok {
+		return t
+	}
+	return tokenizers[Estimate]
+}
+
+// Registered reports whether a tokenizer is registered under name, rather
+// than Get falling back to the estimator
+func Registered(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := tokenizers[name]
+	return ok
+}
+
+// ForModel picks the tokenizer for a model from its "tokenizer" parameter,
+// or from its type when the parameter is not set
+func ForModel(modelType string, parameters map[string]string) Tokenizer {
+	if name := parameters["tokenizer"]; name != "" {
+		return Get(name)
+	}
+
+	switch {
+	case strings.HasPrefix(modelType, "gpt-4"), strings.HasPrefix(modelType, "gpt-3.5"):
+		return Get(CL100KBase)
+	default:
+		return Get(Estimate)
+	}
+}
+
+// LoadFromEnv registers the cl100k_base BPE tokenizer when RanksFileEnv is
+// set. Without it, cl100k models are counted by the estimator.
+func LoadFromEnv() error {
+	path := os.Getenv(RanksFileEnv)
+	if path == "" {
+		return nil
+	}
+
+	bpe, err := LoadBPE(CL100KBase, path)
+	if err != nil {
+		return err
+	}
+	Register(CL100KBase, bpe)
+	return nil
+}
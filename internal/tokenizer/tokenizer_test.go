@@ -0,0 +1,15 @@
+package tokenizer
+
+import "testing"
+
+func TestRegistered(t *testing.T) {
+	if !Registered(Estimate) {
+		t.Error("the estimator is not registered")
+	}
+	if Registered("unknown") {
+		t.Error("an unknown tokenizer is registered")
+	}
+	if got := Get("unknown").Name(); got != Estimate {
+		t.Errorf("Get(unknown) = %s, want the %s fallback", got, Estimate)
+	}
+}
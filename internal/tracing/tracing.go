@@ -0,0 +1,162 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporters spans can be sent to
+const (
+	ExporterNone   = ""
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config selects where spans are exported
+type Config struct {
+	Exporter    string  // ExporterOTLP, ExporterStdout, or none to disable tracing
+	Endpoint    string  // OTLP gRPC collector, from OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4317 if empty
+	Insecure    bool    // connect to the OTLP collector without TLS
+	ServiceName string  // service.name of the spans, "mcp-server" by default
+	SampleRatio float64 // share of new traces recorded, all when zero
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The returned function flushes pending spans and stops the
+// exporter. With no exporter it installs nothing, leaving spans unrecorded.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, must be %q or %q", cfg.Exporter, ExporterOTLP, ExporterStdout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating %s trace exporter: %v", cfg.Exporter, err)
+	}
+
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = "mcp-server"
+	}
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Inject returns the trace context of ctx as a map, to be stored with work
+// that is carried on elsewhere, such as queued executions. It returns nil
+// when ctx is not traced.
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns ctx continuing the trace context stored by Inject
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+var tracer = otel.Tracer("github.com/DavutcanJ/mongo-mcp-server/internal/tracing")
+
+// CommandMonitor traces the commands sent to MongoDB as children of the
+// span of the operation's context, and then calls next if set
+func CommandMonitor(next *event.CommandMonitor) *event.CommandMonitor {
+	var mu sync.Mutex
+	spans := make(map[int64]trace.Span)
+
+	end := func(requestID int64, err error) {
+		mu.Lock()
+		span, ok := spans[requestID]
+		delete(spans, requestID)
+		mu.Unlock()
+		if !ok {
+			return
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			attrs := []attribute.KeyValue{
+				semconv.DBSystemMongoDB,
+				semconv.DBName(e.DatabaseName),
+				semconv.DBOperation(e.CommandName),
+			}
+			if collection, ok := e.Command.Lookup(e.CommandName).StringValueOK(); ok {
+				attrs = append(attrs, semconv.DBMongoDBCollection(collection))
+			}
+			_, span := tracer.Start(ctx, "mongodb."+e.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...))
+
+			mu.Lock()
+			spans[e.RequestID] = span
+			mu.Unlock()
+
+			if next != nil && next.Started != nil {
+				next.Started(ctx, e)
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			end(e.RequestID, nil)
+			if next != nil && next.Succeeded != nil {
+				next.Succeeded(ctx, e)
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			end(e.RequestID, fmt.Errorf("%s", e.Failure))
+			if next != nil && next.Failed != nil {
+				next.Failed(ctx, e)
+			}
+		},
+	}
+}
@@ -0,0 +1,133 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recorder holds the spans ended during the tests. The global tracer
+// provider can only be set once for the package tracer, so all tests share it.
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	if _, err := Setup(context.Background(), Config{}); err != nil {
+		panic(err)
+	}
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	os.Exit(m.Run())
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	if err != nil {
+		t.Fatalf("Setup without an exporter: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+
+	if _, err := Setup(context.Background(), Config{Exporter: "zipkin"}); err == nil {
+		t.Error("Setup accepted an unknown exporter")
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	if carrier := Inject(context.Background()); carrier != nil {
+		t.Errorf("Inject of an untraced context = %v, want nil", carrier)
+	}
+	if ctx := Extract(context.Background(), nil); trace.SpanContextFromContext(ctx).IsValid() {
+		t.Error("Extract of no carrier returned a trace")
+	}
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "submit")
+	defer span.End()
+	carrier := Inject(ctx)
+	if carrier["traceparent"] == "" {
+		t.Fatalf("Inject = %v, want a traceparent", carrier)
+	}
+
+	// The execution continues the trace of its submission
+	remote := trace.SpanContextFromContext(Extract(context.Background(), carrier))
+	if !remote.IsRemote() || remote.TraceID() != span.SpanContext().TraceID() || remote.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("Extract = %v, want the span %v", remote, span.SpanContext())
+	}
+}
+
+// command returns a started event for the command name on collection
+func command(t *testing.T, requestID int64, name, collection string) *event.CommandStartedEvent {
+	t.Helper()
+	raw, err := bson.Marshal(bson.D{{Key: name, Value: collection}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &event.CommandStartedEvent{
+		Command:      raw,
+		DatabaseName: "mcp",
+		CommandName:  name,
+		RequestID:    requestID,
+	}
+}
+
+// ended returns the ended span named name
+func ended(t *testing.T, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("no span %q ended", name)
+	return nil
+}
+
+func TestCommandMonitor(t *testing.T) {
+	var calls []string
+	next := &event.CommandMonitor{
+		Started:   func(context.Context, *event.CommandStartedEvent) { calls = append(calls, "started") },
+		Succeeded: func(context.Context, *event.CommandSucceededEvent) { calls = append(calls, "succeeded") },
+		Failed:    func(context.Context, *event.CommandFailedEvent) { calls = append(calls, "failed") },
+	}
+	monitor := CommandMonitor(next)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "GetExecution")
+	monitor.Started(ctx, command(t, 1, "find", "executions"))
+	monitor.Started(ctx, command(t, 2, "insert", "batches"))
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{RequestID: 1, CommandName: "find"}})
+	monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: event.CommandFinishedEvent{RequestID: 2, CommandName: "insert"}, Failure: "duplicate key"})
+	// A command that finishes without having started is ignored
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{RequestID: 3, CommandName: "find"}})
+	parent.End()
+
+	find := ended(t, "mongodb.find")
+	if find.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("command span is not a child of the operation span")
+	}
+	if find.SpanKind() != trace.SpanKindClient || find.Status().Code == codes.Error {
+		t.Errorf("find span kind %s status %v, want a successful client span", find.SpanKind(), find.Status())
+	}
+	attrs := attribute.NewSet(find.Attributes()...)
+	if v, _ := attrs.Value("db.mongodb.collection"); v.AsString() != "executions" {
+		t.Errorf("find span collection = %q, want executions", v.AsString())
+	}
+
+	insert := ended(t, "mongodb.insert")
+	if insert.Status().Code != codes.Error || insert.Status().Description != "duplicate key" {
+		t.Errorf("insert span status = %v, want the failure", insert.Status())
+	}
+
+	want := []string{"started", "started", "succeeded", "failed", "succeeded"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("next monitor got %v, want %v", calls, want)
+	}
+}
@@ -19,18 +19,54 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	MCPService_CreateModel_FullMethodName       = "/mcp.MCPService/CreateModel"
-	MCPService_GetModel_FullMethodName          = "/mcp.MCPService/GetModel"
-	MCPService_ListModels_FullMethodName        = "/mcp.MCPService/ListModels"
-	MCPService_CreateContext_FullMethodName     = "/mcp.MCPService/CreateContext"
-	MCPService_GetContext_FullMethodName        = "/mcp.MCPService/GetContext"
-	MCPService_ListContexts_FullMethodName      = "/mcp.MCPService/ListContexts"
-	MCPService_ExecuteProtocol_FullMethodName   = "/mcp.MCPService/ExecuteProtocol"
-	MCPService_GetProtocolStatus_FullMethodName = "/mcp.MCPService/GetProtocolStatus"
-	MCPService_AddData_FullMethodName           = "/mcp.MCPService/AddData"
-	MCPService_GetData_FullMethodName           = "/mcp.MCPService/GetData"
-	MCPService_ListData_FullMethodName          = "/mcp.MCPService/ListData"
-	MCPService_DeleteData_FullMethodName        = "/mcp.MCPService/DeleteData"
+	MCPService_CreateModel_FullMethodName          = "/mcp.MCPService/CreateModel"
+	MCPService_GetModel_FullMethodName             = "/mcp.MCPService/GetModel"
+	MCPService_ListModels_FullMethodName           = "/mcp.MCPService/ListModels"
+	MCPService_GetModelHealth_FullMethodName       = "/mcp.MCPService/GetModelHealth"
+	MCPService_CreateModelVersion_FullMethodName   = "/mcp.MCPService/CreateModelVersion"
+	MCPService_GetModelSchema_FullMethodName       = "/mcp.MCPService/GetModelSchema"
+	MCPService_ListModelVersions_FullMethodName    = "/mcp.MCPService/ListModelVersions"
+	MCPService_SetModelAlias_FullMethodName        = "/mcp.MCPService/SetModelAlias"
+	MCPService_ListModelAliases_FullMethodName     = "/mcp.MCPService/ListModelAliases"
+	MCPService_DeleteModelAlias_FullMethodName     = "/mcp.MCPService/DeleteModelAlias"
+	MCPService_CreateContext_FullMethodName        = "/mcp.MCPService/CreateContext"
+	MCPService_GetContext_FullMethodName           = "/mcp.MCPService/GetContext"
+	MCPService_ListContexts_FullMethodName         = "/mcp.MCPService/ListContexts"
+	MCPService_LinkContextModel_FullMethodName     = "/mcp.MCPService/LinkContextModel"
+	MCPService_UnlinkContextModel_FullMethodName   = "/mcp.MCPService/UnlinkContextModel"
+	MCPService_ListContextsForModel_FullMethodName = "/mcp.MCPService/ListContextsForModel"
+	MCPService_CreateProtocol_FullMethodName       = "/mcp.MCPService/CreateProtocol"
+	MCPService_GetProtocol_FullMethodName          = "/mcp.MCPService/GetProtocol"
+	MCPService_ListProtocols_FullMethodName        = "/mcp.MCPService/ListProtocols"
+	MCPService_UpdateProtocol_FullMethodName       = "/mcp.MCPService/UpdateProtocol"
+	MCPService_DeleteProtocol_FullMethodName       = "/mcp.MCPService/DeleteProtocol"
+	MCPService_ExecuteProtocol_FullMethodName      = "/mcp.MCPService/ExecuteProtocol"
+	MCPService_GetProtocolStatus_FullMethodName    = "/mcp.MCPService/GetProtocolStatus"
+	MCPService_StreamExecution_FullMethodName      = "/mcp.MCPService/StreamExecution"
+	MCPService_WatchExecution_FullMethodName       = "/mcp.MCPService/WatchExecution"
+	MCPService_CancelExecution_FullMethodName      = "/mcp.MCPService/CancelExecution"
+	MCPService_ListExecutions_FullMethodName       = "/mcp.MCPService/ListExecutions"
+	MCPService_GetExecution_FullMethodName         = "/mcp.MCPService/GetExecution"
+	MCPService_CountTokens_FullMethodName          = "/mcp.MCPService/CountTokens"
+	MCPService_GetQueueStats_FullMethodName        = "/mcp.MCPService/GetQueueStats"
+	MCPService_GetUsageReport_FullMethodName       = "/mcp.MCPService/GetUsageReport"
+	MCPService_ExecuteBatch_FullMethodName         = "/mcp.MCPService/ExecuteBatch"
+	MCPService_GetBatchStatus_FullMethodName       = "/mcp.MCPService/GetBatchStatus"
+	MCPService_ExportBatch_FullMethodName          = "/mcp.MCPService/ExportBatch"
+	MCPService_PurgeCache_FullMethodName           = "/mcp.MCPService/PurgeCache"
+	MCPService_SetLogLevel_FullMethodName          = "/mcp.MCPService/SetLogLevel"
+	MCPService_CreateSchedule_FullMethodName       = "/mcp.MCPService/CreateSchedule"
+	MCPService_ListSchedules_FullMethodName        = "/mcp.MCPService/ListSchedules"
+	MCPService_PauseSchedule_FullMethodName        = "/mcp.MCPService/PauseSchedule"
+	MCPService_DeleteSchedule_FullMethodName       = "/mcp.MCPService/DeleteSchedule"
+	MCPService_AddData_FullMethodName              = "/mcp.MCPService/AddData"
+	MCPService_GetData_FullMethodName              = "/mcp.MCPService/GetData"
+	MCPService_ListData_FullMethodName             = "/mcp.MCPService/ListData"
+	MCPService_DeleteData_FullMethodName           = "/mcp.MCPService/DeleteData"
+	MCPService_PutSecret_FullMethodName            = "/mcp.MCPService/PutSecret"
+	MCPService_ListSecrets_FullMethodName          = "/mcp.MCPService/ListSecrets"
+	MCPService_DeleteSecret_FullMethodName         = "/mcp.MCPService/DeleteSecret"
+	MCPService_Subscribe_FullMethodName            = "/mcp.MCPService/Subscribe"
 )
 
 // MCPServiceClient is the client API for MCPService service.
@@ -41,18 +77,59 @@ type MCPServiceClient interface {
 	CreateModel(ctx context.Context, in *Model, opts ...grpc.CallOption) (*ModelResponse, error)
 	GetModel(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*ModelResponse, error)
 	ListModels(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ModelList, error)
+	GetModelHealth(ctx context.Context, in *ModelHealthRequest, opts ...grpc.CallOption) (*ModelHealthResponse, error)
+	CreateModelVersion(ctx context.Context, in *Model, opts ...grpc.CallOption) (*ModelResponse, error)
+	GetModelSchema(ctx context.Context, in *ModelSchemaRequest, opts ...grpc.CallOption) (*ModelSchema, error)
+	ListModelVersions(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*ModelList, error)
+	SetModelAlias(ctx context.Context, in *ModelAlias, opts ...grpc.CallOption) (*ModelAliasResponse, error)
+	ListModelAliases(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*ModelAliasList, error)
+	DeleteModelAlias(ctx context.Context, in *ModelAlias, opts ...grpc.CallOption) (*DeleteResponse, error)
 	// Context operations
 	CreateContext(ctx context.Context, in *Context, opts ...grpc.CallOption) (*ContextResponse, error)
 	GetContext(ctx context.Context, in *ContextRequest, opts ...grpc.CallOption) (*ContextResponse, error)
 	ListContexts(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ContextList, error)
+	LinkContextModel(ctx context.Context, in *ContextModelRequest, opts ...grpc.CallOption) (*ContextResponse, error)
+	UnlinkContextModel(ctx context.Context, in *ContextModelRequest, opts ...grpc.CallOption) (*ContextResponse, error)
+	ListContextsForModel(ctx context.Context, in *ModelContextsRequest, opts ...grpc.CallOption) (*ContextList, error)
+	// Protocol definition operations
+	CreateProtocol(ctx context.Context, in *ProtocolDefinition, opts ...grpc.CallOption) (*ProtocolDefinitionResponse, error)
+	GetProtocol(ctx context.Context, in *ProtocolDefinitionRequest, opts ...grpc.CallOption) (*ProtocolDefinitionResponse, error)
+	ListProtocols(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ProtocolDefinitionList, error)
+	UpdateProtocol(ctx context.Context, in *ProtocolDefinition, opts ...grpc.CallOption) (*ProtocolDefinitionResponse, error)
+	DeleteProtocol(ctx context.Context, in *ProtocolDefinitionRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
 	// Protocol operations
 	ExecuteProtocol(ctx context.Context, in *Protocol, opts ...grpc.CallOption) (*ProtocolResponse, error)
 	GetProtocolStatus(ctx context.Context, in *ProtocolRequest, opts ...grpc.CallOption) (*ProtocolStatus, error)
+	StreamExecution(ctx context.Context, in *Protocol, opts ...grpc.CallOption) (MCPService_StreamExecutionClient, error)
+	WatchExecution(ctx context.Context, in *ProtocolRequest, opts ...grpc.CallOption) (MCPService_WatchExecutionClient, error)
+	CancelExecution(ctx context.Context, in *ProtocolRequest, opts ...grpc.CallOption) (*ProtocolStatus, error)
+	ListExecutions(ctx context.Context, in *ListExecutionsRequest, opts ...grpc.CallOption) (*ExecutionList, error)
+	GetExecution(ctx context.Context, in *ProtocolRequest, opts ...grpc.CallOption) (*ExecutionResponse, error)
+	CountTokens(ctx context.Context, in *TokenCountRequest, opts ...grpc.CallOption) (*TokenCountResponse, error)
+	GetQueueStats(ctx context.Context, in *QueueStatsRequest, opts ...grpc.CallOption) (*QueueStats, error)
+	GetUsageReport(ctx context.Context, in *UsageReportRequest, opts ...grpc.CallOption) (*UsageReport, error)
+	ExecuteBatch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	GetBatchStatus(ctx context.Context, in *BatchStatusRequest, opts ...grpc.CallOption) (*BatchStatus, error)
+	ExportBatch(ctx context.Context, in *BatchStatusRequest, opts ...grpc.CallOption) (MCPService_ExportBatchClient, error)
+	// Admin operations
+	PurgeCache(ctx context.Context, in *PurgeCacheRequest, opts ...grpc.CallOption) (*PurgeCacheResponse, error)
+	SetLogLevel(ctx context.Context, in *LogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error)
+	// Schedule operations
+	CreateSchedule(ctx context.Context, in *Schedule, opts ...grpc.CallOption) (*ScheduleResponse, error)
+	ListSchedules(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ScheduleList, error)
+	PauseSchedule(ctx context.Context, in *PauseScheduleRequest, opts ...grpc.CallOption) (*ScheduleResponse, error)
+	DeleteSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
 	// Data operations
 	AddData(ctx context.Context, in *Data, opts ...grpc.CallOption) (*DataResponse, error)
 	GetData(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*DataResponse, error)
 	ListData(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*DataList, error)
 	DeleteData(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Secret operations
+	PutSecret(ctx context.Context, in *Secret, opts ...grpc.CallOption) (*SecretResponse, error)
+	ListSecrets(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*SecretList, error)
+	DeleteSecret(ctx context.Context, in *SecretRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Change operations
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (MCPService_SubscribeClient, error)
 }
 
 type mCPServiceClient struct {
@@ -90,6 +167,69 @@ func (c *mCPServiceClient) ListModels(ctx context.Context, in *ListRequest, opts
 	return out, nil
 }
 
+func (c *mCPServiceClient) GetModelHealth(ctx context.Context, in *ModelHealthRequest, opts ...grpc.CallOption) (*ModelHealthResponse, error) {
+	out := new(ModelHealthResponse)
+	err := c.cc.Invoke(ctx, MCPService_GetModelHealth_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) CreateModelVersion(ctx context.Context, in *Model, opts ...grpc.CallOption) (*ModelResponse, error) {
+	out := new(ModelResponse)
+	err := c.cc.Invoke(ctx, MCPService_CreateModelVersion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) GetModelSchema(ctx context.Context, in *ModelSchemaRequest, opts ...grpc.CallOption) (*ModelSchema, error) {
+	out := new(ModelSchema)
+	err := c.cc.Invoke(ctx, MCPService_GetModelSchema_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) ListModelVersions(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*ModelList, error) {
+	out := new(ModelList)
+	err := c.cc.Invoke(ctx, MCPService_ListModelVersions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) SetModelAlias(ctx context.Context, in *ModelAlias, opts ...grpc.CallOption) (*ModelAliasResponse, error) {
+	out := new(ModelAliasResponse)
+	err := c.cc.Invoke(ctx, MCPService_SetModelAlias_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) ListModelAliases(ctx context.Context, in *ModelRequest, opts ...grpc.CallOption) (*ModelAliasList, error) {
+	out := new(ModelAliasList)
+	err := c.cc.Invoke(ctx, MCPService_ListModelAliases_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) DeleteModelAlias(ctx context.Context, in *ModelAlias, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, MCPService_DeleteModelAlias_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mCPServiceClient) CreateContext(ctx context.Context, in *Context, opts ...grpc.CallOption) (*ContextResponse, error) {
 	out := new(ContextResponse)
 	err := c.cc.Invoke(ctx, MCPService_CreateContext_FullMethodName, in, out, opts...)
@@ -117,6 +257,78 @@ func (c *mCPServiceClient) ListContexts(ctx context.Context, in *ListRequest, op
 	return out, nil
 }
 
+func (c *mCPServiceClient) LinkContextModel(ctx context.Context, in *ContextModelRequest, opts ...grpc.CallOption) (*ContextResponse, error) {
+	out := new(ContextResponse)
+	err := c.cc.Invoke(ctx, MCPService_LinkContextModel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) UnlinkContextModel(ctx context.Context, in *ContextModelRequest, opts ...grpc.CallOption) (*ContextResponse, error) {
+	out := new(ContextResponse)
+	err := c.cc.Invoke(ctx, MCPService_UnlinkContextModel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) ListContextsForModel(ctx context.Context, in *ModelContextsRequest, opts ...grpc.CallOption) (*ContextList, error) {
+	out := new(ContextList)
+	err := c.cc.Invoke(ctx, MCPService_ListContextsForModel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) CreateProtocol(ctx context.Context, in *ProtocolDefinition, opts ...grpc.CallOption) (*ProtocolDefinitionResponse, error) {
+	out := new(ProtocolDefinitionResponse)
+	err := c.cc.Invoke(ctx, MCPService_CreateProtocol_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) GetProtocol(ctx context.Context, in *ProtocolDefinitionRequest, opts ...grpc.CallOption) (*ProtocolDefinitionResponse, error) {
+	out := new(ProtocolDefinitionResponse)
+	err := c.cc.Invoke(ctx, MCPService_GetProtocol_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) ListProtocols(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ProtocolDefinitionList, error) {
+	out := new(ProtocolDefinitionList)
+	err := c.cc.Invoke(ctx, MCPService_ListProtocols_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) UpdateProtocol(ctx context.Context, in *ProtocolDefinition, opts ...grpc.CallOption) (*ProtocolDefinitionResponse, error) {
+	out := new(ProtocolDefinitionResponse)
+	err := c.cc.Invoke(ctx, MCPService_UpdateProtocol_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) DeleteProtocol(ctx context.Context, in *ProtocolDefinitionRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, MCPService_DeleteProtocol_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mCPServiceClient) ExecuteProtocol(ctx context.Context, in *Protocol, opts ...grpc.CallOption) (*ProtocolResponse, error) {
 	out := new(ProtocolResponse)
 	err := c.cc.Invoke(ctx, MCPService_ExecuteProtocol_FullMethodName, in, out, opts...)
@@ -135,258 +347,1273 @@ func (c *mCPServiceClient) GetProtocolStatus(ctx context.Context, in *ProtocolRe
 	return out, nil
 }
 
-func (c *mCPServiceClient) AddData(ctx context.Context, in *Data, opts ...grpc.CallOption) (*DataResponse, error) {
-	out := new(DataResponse)
-	err := c.cc.Invoke(ctx, MCPService_AddData_FullMethodName, in, out, opts...)
+func (c *mCPServiceClient) StreamExecution(ctx context.Context, in *Protocol, opts ...grpc.CallOption) (MCPService_StreamExecutionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MCPService_ServiceDesc.Streams[0], MCPService_StreamExecution_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mCPServiceStreamExecutionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MCPService_StreamExecutionClient interface {
+	Recv() (*ExecutionEvent, error)
+	grpc.ClientStream
+}
+
+type mCPServiceStreamExecutionClient struct {
+	grpc.ClientStream
+}
+
+func (x *mCPServiceStreamExecutionClient) Recv() (*ExecutionEvent, error) {
+	m := new(ExecutionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mCPServiceClient) WatchExecution(ctx context.Context, in *ProtocolRequest, opts ...grpc.CallOption) (MCPService_WatchExecutionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MCPService_ServiceDesc.Streams[1], MCPService_WatchExecution_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mCPServiceWatchExecutionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MCPService_WatchExecutionClient interface {
+	Recv() (*ExecutionEvent, error)
+	grpc.ClientStream
+}
+
+type mCPServiceWatchExecutionClient struct {
+	grpc.ClientStream
+}
+
+func (x *mCPServiceWatchExecutionClient) Recv() (*ExecutionEvent, error) {
+	m := new(ExecutionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mCPServiceClient) CancelExecution(ctx context.Context, in *ProtocolRequest, opts ...grpc.CallOption) (*ProtocolStatus, error) {
+	out := new(ProtocolStatus)
+	err := c.cc.Invoke(ctx, MCPService_CancelExecution_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *mCPServiceClient) GetData(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*DataResponse, error) {
-	out := new(DataResponse)
-	err := c.cc.Invoke(ctx, MCPService_GetData_FullMethodName, in, out, opts...)
+func (c *mCPServiceClient) ListExecutions(ctx context.Context, in *ListExecutionsRequest, opts ...grpc.CallOption) (*ExecutionList, error) {
+	out := new(ExecutionList)
+	err := c.cc.Invoke(ctx, MCPService_ListExecutions_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *mCPServiceClient) ListData(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*DataList, error) {
-	out := new(DataList)
-	err := c.cc.Invoke(ctx, MCPService_ListData_FullMethodName, in, out, opts...)
+func (c *mCPServiceClient) GetExecution(ctx context.Context, in *ProtocolRequest, opts ...grpc.CallOption) (*ExecutionResponse, error) {
+	out := new(ExecutionResponse)
+	err := c.cc.Invoke(ctx, MCPService_GetExecution_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *mCPServiceClient) DeleteData(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
-	out := new(DeleteResponse)
-	err := c.cc.Invoke(ctx, MCPService_DeleteData_FullMethodName, in, out, opts...)
+func (c *mCPServiceClient) CountTokens(ctx context.Context, in *TokenCountRequest, opts ...grpc.CallOption) (*TokenCountResponse, error) {
+	out := new(TokenCountResponse)
+	err := c.cc.Invoke(ctx, MCPService_CountTokens_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// MCPServiceServer is the server API for MCPService service.
-// All implementations must embed UnimplementedMCPServiceServer
-// for forward compatibility
-type MCPServiceServer interface {
-	// Model operations
-	CreateModel(context.Context, *Model) (*ModelResponse, error)
-	GetModel(context.Context, *ModelRequest) (*ModelResponse, error)
-	ListModels(context.Context, *ListRequest) (*ModelList, error)
-	// Context operations
-	CreateContext(context.Context, *Context) (*ContextResponse, error)
-	GetContext(context.Context, *ContextRequest) (*ContextResponse, error)
-	ListContexts(context.Context, *ListRequest) (*ContextList, error)
-	// Protocol operations
-	ExecuteProtocol(context.Context, *Protocol) (*ProtocolResponse, error)
-	GetProtocolStatus(context.Context, *ProtocolRequest) (*ProtocolStatus, error)
-	// Data operations
-	AddData(context.Context, *Data) (*DataResponse, error)
-	GetData(context.Context, *DataRequest) (*DataResponse, error)
-	ListData(context.Context, *ListRequest) (*DataList, error)
-	DeleteData(context.Context, *DataRequest) (*DeleteResponse, error)
-	mustEmbedUnimplementedMCPServiceServer()
+func (c *mCPServiceClient) GetQueueStats(ctx context.Context, in *QueueStatsRequest, opts ...grpc.CallOption) (*QueueStats, error) {
+	out := new(QueueStats)
+	err := c.cc.Invoke(ctx, MCPService_GetQueueStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedMCPServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedMCPServiceServer struct {
+func (c *mCPServiceClient) GetUsageReport(ctx context.Context, in *UsageReportRequest, opts ...grpc.CallOption) (*UsageReport, error) {
+	out := new(UsageReport)
+	err := c.cc.Invoke(ctx, MCPService_GetUsageReport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (UnimplementedMCPServiceServer) CreateModel(context.Context, *Model) (*ModelResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateModel not implemented")
+func (c *mCPServiceClient) ExecuteBatch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, MCPService_ExecuteBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMCPServiceServer) GetModel(context.Context, *ModelRequest) (*ModelResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetModel not implemented")
+
+func (c *mCPServiceClient) GetBatchStatus(ctx context.Context, in *BatchStatusRequest, opts ...grpc.CallOption) (*BatchStatus, error) {
+	out := new(BatchStatus)
+	err := c.cc.Invoke(ctx, MCPService_GetBatchStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMCPServiceServer) ListModels(context.Context, *ListRequest) (*ModelList, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListModels not implemented")
+
+func (c *mCPServiceClient) ExportBatch(ctx context.Context, in *BatchStatusRequest, opts ...grpc.CallOption) (MCPService_ExportBatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MCPService_ServiceDesc.Streams[2], MCPService_ExportBatch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mCPServiceExportBatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
-func (UnimplementedMCPServiceServer) CreateContext(context.Context, *Context) (*ContextResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateContext not implemented")
+
+type MCPService_ExportBatchClient interface {
+	Recv() (*Execution, error)
+	grpc.ClientStream
 }
-func (UnimplementedMCPServiceServer) GetContext(context.Context, *ContextRequest) (*ContextResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetContext not implemented")
+
+type mCPServiceExportBatchClient struct {
+	grpc.ClientStream
 }
-func (UnimplementedMCPServiceServer) ListContexts(context.Context, *ListRequest) (*ContextList, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListContexts not implemented")
+
+func (x *mCPServiceExportBatchClient) Recv() (*Execution, error) {
+	m := new(Execution)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
-func (UnimplementedMCPServiceServer) ExecuteProtocol(context.Context, *Protocol) (*ProtocolResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ExecuteProtocol not implemented")
+
+func (c *mCPServiceClient) PurgeCache(ctx context.Context, in *PurgeCacheRequest, opts ...grpc.CallOption) (*PurgeCacheResponse, error) {
+	out := new(PurgeCacheResponse)
+	err := c.cc.Invoke(ctx, MCPService_PurgeCache_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMCPServiceServer) GetProtocolStatus(context.Context, *ProtocolRequest) (*ProtocolStatus, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetProtocolStatus not implemented")
+
+func (c *mCPServiceClient) SetLogLevel(ctx context.Context, in *LogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error) {
+	out := new(LogLevelResponse)
+	err := c.cc.Invoke(ctx, MCPService_SetLogLevel_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMCPServiceServer) AddData(context.Context, *Data) (*DataResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddData not implemented")
+
+func (c *mCPServiceClient) CreateSchedule(ctx context.Context, in *Schedule, opts ...grpc.CallOption) (*ScheduleResponse, error) {
+	out := new(ScheduleResponse)
+	err := c.cc.Invoke(ctx, MCPService_CreateSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) ListSchedules(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ScheduleList, error) {
+	out := new(ScheduleList)
+	err := c.cc.Invoke(ctx, MCPService_ListSchedules_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) PauseSchedule(ctx context.Context, in *PauseScheduleRequest, opts ...grpc.CallOption) (*ScheduleResponse, error) {
+	out := new(ScheduleResponse)
+	err := c.cc.Invoke(ctx, MCPService_PauseSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) DeleteSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, MCPService_DeleteSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) AddData(ctx context.Context, in *Data, opts ...grpc.CallOption) (*DataResponse, error) {
+	out := new(DataResponse)
+	err := c.cc.Invoke(ctx, MCPService_AddData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) GetData(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*DataResponse, error) {
+	out := new(DataResponse)
+	err := c.cc.Invoke(ctx, MCPService_GetData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) ListData(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*DataList, error) {
+	out := new(DataList)
+	err := c.cc.Invoke(ctx, MCPService_ListData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) DeleteData(ctx context.Context, in *DataRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, MCPService_DeleteData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) PutSecret(ctx context.Context, in *Secret, opts ...grpc.CallOption) (*SecretResponse, error) {
+	out := new(SecretResponse)
+	err := c.cc.Invoke(ctx, MCPService_PutSecret_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) ListSecrets(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*SecretList, error) {
+	out := new(SecretList)
+	err := c.cc.Invoke(ctx, MCPService_ListSecrets_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) DeleteSecret(ctx context.Context, in *SecretRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, MCPService_DeleteSecret_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mCPServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (MCPService_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MCPService_ServiceDesc.Streams[3], MCPService_Subscribe_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mCPServiceSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MCPService_SubscribeClient interface {
+	Recv() (*ChangeEvent, error)
+	grpc.ClientStream
+}
+
+type mCPServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *mCPServiceSubscribeClient) Recv() (*ChangeEvent, error) {
+	m := new(ChangeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MCPServiceServer is the server API for MCPService service.
+// All implementations must embed UnimplementedMCPServiceServer
+// for forward compatibility
+type MCPServiceServer interface {
+	// Model operations
+	CreateModel(context.Context, *Model) (*ModelResponse, error)
+	GetModel(context.Context, *ModelRequest) (*ModelResponse, error)
+	ListModels(context.Context, *ListRequest) (*ModelList, error)
+	GetModelHealth(context.Context, *ModelHealthRequest) (*ModelHealthResponse, error)
+	CreateModelVersion(context.Context, *Model) (*ModelResponse, error)
+	GetModelSchema(context.Context, *ModelSchemaRequest) (*ModelSchema, error)
+	ListModelVersions(context.Context, *ModelRequest) (*ModelList, error)
+	SetModelAlias(context.Context, *ModelAlias) (*ModelAliasResponse, error)
+	ListModelAliases(context.Context, *ModelRequest) (*ModelAliasList, error)
+	DeleteModelAlias(context.Context, *ModelAlias) (*DeleteResponse, error)
+	// Context operations
+	CreateContext(context.Context, *Context) (*ContextResponse, error)
+	GetContext(context.Context, *ContextRequest) (*ContextResponse, error)
+	ListContexts(context.Context, *ListRequest) (*ContextList, error)
+	LinkContextModel(context.Context, *ContextModelRequest) (*ContextResponse, error)
+	UnlinkContextModel(context.Context, *ContextModelRequest) (*ContextResponse, error)
+	ListContextsForModel(context.Context, *ModelContextsRequest) (*ContextList, error)
+	// Protocol definition operations
+	CreateProtocol(context.Context, *ProtocolDefinition) (*ProtocolDefinitionResponse, error)
+	GetProtocol(context.Context, *ProtocolDefinitionRequest) (*ProtocolDefinitionResponse, error)
+	ListProtocols(context.Context, *ListRequest) (*ProtocolDefinitionList, error)
+	UpdateProtocol(context.Context, *ProtocolDefinition) (*ProtocolDefinitionResponse, error)
+	DeleteProtocol(context.Context, *ProtocolDefinitionRequest) (*DeleteResponse, error)
+	// Protocol operations
+	ExecuteProtocol(context.Context, *Protocol) (*ProtocolResponse, error)
+	GetProtocolStatus(context.Context, *ProtocolRequest) (*ProtocolStatus, error)
+	StreamExecution(*Protocol, MCPService_StreamExecutionServer) error
+	WatchExecution(*ProtocolRequest, MCPService_WatchExecutionServer) error
+	CancelExecution(context.Context, *ProtocolRequest) (*ProtocolStatus, error)
+	ListExecutions(context.Context, *ListExecutionsRequest) (*ExecutionList, error)
+	GetExecution(context.Context, *ProtocolRequest) (*ExecutionResponse, error)
+	CountTokens(context.Context, *TokenCountRequest) (*TokenCountResponse, error)
+	GetQueueStats(context.Context, *QueueStatsRequest) (*QueueStats, error)
+	GetUsageReport(context.Context, *UsageReportRequest) (*UsageReport, error)
+	ExecuteBatch(context.Context, *BatchRequest) (*BatchResponse, error)
+	GetBatchStatus(context.Context, *BatchStatusRequest) (*BatchStatus, error)
+	ExportBatch(*BatchStatusRequest, MCPService_ExportBatchServer) error
+	// Admin operations
+	PurgeCache(context.Context, *PurgeCacheRequest) (*PurgeCacheResponse, error)
+	SetLogLevel(context.Context, *LogLevelRequest) (*LogLevelResponse, error)
+	// Schedule operations
+	CreateSchedule(context.Context, *Schedule) (*ScheduleResponse, error)
+	ListSchedules(context.Context, *ListRequest) (*ScheduleList, error)
+	PauseSchedule(context.Context, *PauseScheduleRequest) (*ScheduleResponse, error)
+	DeleteSchedule(context.Context, *ScheduleRequest) (*DeleteResponse, error)
+	// Data operations
+	AddData(context.Context, *Data) (*DataResponse, error)
+	GetData(context.Context, *DataRequest) (*DataResponse, error)
+	ListData(context.Context, *ListRequest) (*DataList, error)
+	DeleteData(context.Context, *DataRequest) (*DeleteResponse, error)
+	// Secret operations
+	PutSecret(context.Context, *Secret) (*SecretResponse, error)
+	ListSecrets(context.Context, *ListRequest) (*SecretList, error)
+	DeleteSecret(context.Context, *SecretRequest) (*DeleteResponse, error)
+	// Change operations
+	Subscribe(*SubscribeRequest, MCPService_SubscribeServer) error
+	mustEmbedUnimplementedMCPServiceServer()
+}
+
+// UnimplementedMCPServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMCPServiceServer struct {
+}
+
+func (UnimplementedMCPServiceServer) CreateModel(context.Context, *Model) (*ModelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateModel not implemented")
+}
+func (UnimplementedMCPServiceServer) GetModel(context.Context, *ModelRequest) (*ModelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModel not implemented")
+}
+func (UnimplementedMCPServiceServer) ListModels(context.Context, *ListRequest) (*ModelList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModels not implemented")
+}
+func (UnimplementedMCPServiceServer) GetModelHealth(context.Context, *ModelHealthRequest) (*ModelHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModelHealth not implemented")
+}
+func (UnimplementedMCPServiceServer) CreateModelVersion(context.Context, *Model) (*ModelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateModelVersion not implemented")
+}
+func (UnimplementedMCPServiceServer) GetModelSchema(context.Context, *ModelSchemaRequest) (*ModelSchema, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModelSchema not implemented")
+}
+func (UnimplementedMCPServiceServer) ListModelVersions(context.Context, *ModelRequest) (*ModelList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModelVersions not implemented")
+}
+func (UnimplementedMCPServiceServer) SetModelAlias(context.Context, *ModelAlias) (*ModelAliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetModelAlias not implemented")
+}
+func (UnimplementedMCPServiceServer) ListModelAliases(context.Context, *ModelRequest) (*ModelAliasList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModelAliases not implemented")
+}
+func (UnimplementedMCPServiceServer) DeleteModelAlias(context.Context, *ModelAlias) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteModelAlias not implemented")
+}
+func (UnimplementedMCPServiceServer) CreateContext(context.Context, *Context) (*ContextResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateContext not implemented")
+}
+func (UnimplementedMCPServiceServer) GetContext(context.Context, *ContextRequest) (*ContextResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetContext not implemented")
+}
+func (UnimplementedMCPServiceServer) ListContexts(context.Context, *ListRequest) (*ContextList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListContexts not implemented")
+}
+func (UnimplementedMCPServiceServer) LinkContextModel(context.Context, *ContextModelRequest) (*ContextResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LinkContextModel not implemented")
+}
+func (UnimplementedMCPServiceServer) UnlinkContextModel(context.Context, *ContextModelRequest) (*ContextResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnlinkContextModel not implemented")
+}
+func (UnimplementedMCPServiceServer) ListContextsForModel(context.Context, *ModelContextsRequest) (*ContextList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListContextsForModel not implemented")
+}
+func (UnimplementedMCPServiceServer) CreateProtocol(context.Context, *ProtocolDefinition) (*ProtocolDefinitionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProtocol not implemented")
+}
+func (UnimplementedMCPServiceServer) GetProtocol(context.Context, *ProtocolDefinitionRequest) (*ProtocolDefinitionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProtocol not implemented")
+}
+func (UnimplementedMCPServiceServer) ListProtocols(context.Context, *ListRequest) (*ProtocolDefinitionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProtocols not implemented")
+}
+func (UnimplementedMCPServiceServer) UpdateProtocol(context.Context, *ProtocolDefinition) (*ProtocolDefinitionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProtocol not implemented")
+}
+func (UnimplementedMCPServiceServer) DeleteProtocol(context.Context, *ProtocolDefinitionRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProtocol not implemented")
+}
+func (UnimplementedMCPServiceServer) ExecuteProtocol(context.Context, *Protocol) (*ProtocolResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteProtocol not implemented")
+}
+func (UnimplementedMCPServiceServer) GetProtocolStatus(context.Context, *ProtocolRequest) (*ProtocolStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProtocolStatus not implemented")
+}
+func (UnimplementedMCPServiceServer) StreamExecution(*Protocol, MCPService_StreamExecutionServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamExecution not implemented")
+}
+func (UnimplementedMCPServiceServer) WatchExecution(*ProtocolRequest, MCPService_WatchExecutionServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchExecution not implemented")
+}
+func (UnimplementedMCPServiceServer) CancelExecution(context.Context, *ProtocolRequest) (*ProtocolStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelExecution not implemented")
+}
+func (UnimplementedMCPServiceServer) ListExecutions(context.Context, *ListExecutionsRequest) (*ExecutionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListExecutions not implemented")
+}
+func (UnimplementedMCPServiceServer) GetExecution(context.Context, *ProtocolRequest) (*ExecutionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExecution not implemented")
+}
+func (UnimplementedMCPServiceServer) CountTokens(context.Context, *TokenCountRequest) (*TokenCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountTokens not implemented")
+}
+func (UnimplementedMCPServiceServer) GetQueueStats(context.Context, *QueueStatsRequest) (*QueueStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQueueStats not implemented")
+}
+func (UnimplementedMCPServiceServer) GetUsageReport(context.Context, *UsageReportRequest) (*UsageReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsageReport not implemented")
+}
+func (UnimplementedMCPServiceServer) ExecuteBatch(context.Context, *BatchRequest) (*BatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteBatch not implemented")
+}
+func (UnimplementedMCPServiceServer) GetBatchStatus(context.Context, *BatchStatusRequest) (*BatchStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBatchStatus not implemented")
+}
+func (UnimplementedMCPServiceServer) ExportBatch(*BatchStatusRequest, MCPService_ExportBatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportBatch not implemented")
+}
+func (UnimplementedMCPServiceServer) PurgeCache(context.Context, *PurgeCacheRequest) (*PurgeCacheResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeCache not implemented")
+}
+func (UnimplementedMCPServiceServer) SetLogLevel(context.Context, *LogLevelRequest) (*LogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedMCPServiceServer) CreateSchedule(context.Context, *Schedule) (*ScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSchedule not implemented")
+}
+func (UnimplementedMCPServiceServer) ListSchedules(context.Context, *ListRequest) (*ScheduleList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchedules not implemented")
+}
+func (UnimplementedMCPServiceServer) PauseSchedule(context.Context, *PauseScheduleRequest) (*ScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseSchedule not implemented")
+}
+func (UnimplementedMCPServiceServer) DeleteSchedule(context.Context, *ScheduleRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSchedule not implemented")
+}
+func (UnimplementedMCPServiceServer) AddData(context.Context, *Data) (*DataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddData not implemented")
+}
+func (UnimplementedMCPServiceServer) GetData(context.Context, *DataRequest) (*DataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetData not implemented")
+}
+func (UnimplementedMCPServiceServer) ListData(context.Context, *ListRequest) (*DataList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListData not implemented")
+}
+func (UnimplementedMCPServiceServer) DeleteData(context.Context, *DataRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteData not implemented")
+}
+func (UnimplementedMCPServiceServer) PutSecret(context.Context, *Secret) (*SecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutSecret not implemented")
+}
+func (UnimplementedMCPServiceServer) ListSecrets(context.Context, *ListRequest) (*SecretList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSecrets not implemented")
+}
+func (UnimplementedMCPServiceServer) DeleteSecret(context.Context, *SecretRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSecret not implemented")
+}
+func (UnimplementedMCPServiceServer) Subscribe(*SubscribeRequest, MCPService_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedMCPServiceServer) mustEmbedUnimplementedMCPServiceServer() {}
+
+// UnsafeMCPServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MCPServiceServer will
+// result in compilation errors.
+type UnsafeMCPServiceServer interface {
+	mustEmbedUnimplementedMCPServiceServer()
+}
+
+func RegisterMCPServiceServer(s grpc.ServiceRegistrar, srv MCPServiceServer) {
+	s.RegisterService(&MCPService_ServiceDesc, srv)
+}
+
+func _MCPService_CreateModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Model)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).CreateModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_CreateModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).CreateModel(ctx, req.(*Model))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_GetModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).GetModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_GetModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).GetModel(ctx, req.(*ModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_ListModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).ListModels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_ListModels_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).ListModels(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_GetModelHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).GetModelHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_GetModelHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).GetModelHealth(ctx, req.(*ModelHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_CreateModelVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Model)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).CreateModelVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_CreateModelVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).CreateModelVersion(ctx, req.(*Model))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_GetModelSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).GetModelSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_GetModelSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).GetModelSchema(ctx, req.(*ModelSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_ListModelVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).ListModelVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_ListModelVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).ListModelVersions(ctx, req.(*ModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_SetModelAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelAlias)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).SetModelAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_SetModelAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).SetModelAlias(ctx, req.(*ModelAlias))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_ListModelAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).ListModelAliases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_ListModelAliases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).ListModelAliases(ctx, req.(*ModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_DeleteModelAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelAlias)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).DeleteModelAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_DeleteModelAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).DeleteModelAlias(ctx, req.(*ModelAlias))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_CreateContext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Context)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).CreateContext(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_CreateContext_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).CreateContext(ctx, req.(*Context))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_GetContext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).GetContext(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_GetContext_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).GetContext(ctx, req.(*ContextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_ListContexts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).ListContexts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_ListContexts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).ListContexts(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_LinkContextModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContextModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).LinkContextModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_LinkContextModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).LinkContextModel(ctx, req.(*ContextModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_UnlinkContextModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContextModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).UnlinkContextModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_UnlinkContextModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).UnlinkContextModel(ctx, req.(*ContextModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_ListContextsForModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModelContextsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).ListContextsForModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_ListContextsForModel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).ListContextsForModel(ctx, req.(*ModelContextsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_CreateProtocol_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProtocolDefinition)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).CreateProtocol(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_CreateProtocol_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).CreateProtocol(ctx, req.(*ProtocolDefinition))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_GetProtocol_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProtocolDefinitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).GetProtocol(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_GetProtocol_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).GetProtocol(ctx, req.(*ProtocolDefinitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_ListProtocols_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).ListProtocols(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_ListProtocols_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).ListProtocols(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_UpdateProtocol_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProtocolDefinition)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).UpdateProtocol(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_UpdateProtocol_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).UpdateProtocol(ctx, req.(*ProtocolDefinition))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_DeleteProtocol_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProtocolDefinitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).DeleteProtocol(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_DeleteProtocol_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).DeleteProtocol(ctx, req.(*ProtocolDefinitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_ExecuteProtocol_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Protocol)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).ExecuteProtocol(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_ExecuteProtocol_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).ExecuteProtocol(ctx, req.(*Protocol))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_GetProtocolStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProtocolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).GetProtocolStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_GetProtocolStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).GetProtocolStatus(ctx, req.(*ProtocolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_StreamExecution_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Protocol)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MCPServiceServer).StreamExecution(m, &mCPServiceStreamExecutionServer{stream})
+}
+
+type MCPService_StreamExecutionServer interface {
+	Send(*ExecutionEvent) error
+	grpc.ServerStream
+}
+
+type mCPServiceStreamExecutionServer struct {
+	grpc.ServerStream
+}
+
+func (x *mCPServiceStreamExecutionServer) Send(m *ExecutionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MCPService_WatchExecution_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ProtocolRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MCPServiceServer).WatchExecution(m, &mCPServiceWatchExecutionServer{stream})
+}
+
+type MCPService_WatchExecutionServer interface {
+	Send(*ExecutionEvent) error
+	grpc.ServerStream
+}
+
+type mCPServiceWatchExecutionServer struct {
+	grpc.ServerStream
+}
+
+func (x *mCPServiceWatchExecutionServer) Send(m *ExecutionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MCPService_CancelExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProtocolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).CancelExecution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_CancelExecution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).CancelExecution(ctx, req.(*ProtocolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMCPServiceServer) GetData(context.Context, *DataRequest) (*DataResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetData not implemented")
+
+func _MCPService_ListExecutions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExecutionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).ListExecutions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_ListExecutions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).ListExecutions(ctx, req.(*ListExecutionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMCPServiceServer) ListData(context.Context, *ListRequest) (*DataList, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListData not implemented")
+
+func _MCPService_GetExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProtocolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).GetExecution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_GetExecution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).GetExecution(ctx, req.(*ProtocolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMCPServiceServer) DeleteData(context.Context, *DataRequest) (*DeleteResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteData not implemented")
+
+func _MCPService_CountTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TokenCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).CountTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_CountTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).CountTokens(ctx, req.(*TokenCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedMCPServiceServer) mustEmbedUnimplementedMCPServiceServer() {}
 
-// UnsafeMCPServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to MCPServiceServer will
-// result in compilation errors.
-type UnsafeMCPServiceServer interface {
-	mustEmbedUnimplementedMCPServiceServer()
+func _MCPService_GetQueueStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueueStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).GetQueueStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_GetQueueStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).GetQueueStats(ctx, req.(*QueueStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterMCPServiceServer(s grpc.ServiceRegistrar, srv MCPServiceServer) {
-	s.RegisterService(&MCPService_ServiceDesc, srv)
+func _MCPService_GetUsageReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).GetUsageReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_GetUsageReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).GetUsageReport(ctx, req.(*UsageReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPService_CreateModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Model)
+func _MCPService_ExecuteBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPServiceServer).CreateModel(ctx, in)
+		return srv.(MCPServiceServer).ExecuteBatch(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPService_CreateModel_FullMethodName,
+		FullMethod: MCPService_ExecuteBatch_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPServiceServer).CreateModel(ctx, req.(*Model))
+		return srv.(MCPServiceServer).ExecuteBatch(ctx, req.(*BatchRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPService_GetModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ModelRequest)
+func _MCPService_GetBatchStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchStatusRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPServiceServer).GetModel(ctx, in)
+		return srv.(MCPServiceServer).GetBatchStatus(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPService_GetModel_FullMethodName,
+		FullMethod: MCPService_GetBatchStatus_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPServiceServer).GetModel(ctx, req.(*ModelRequest))
+		return srv.(MCPServiceServer).GetBatchStatus(ctx, req.(*BatchStatusRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPService_ListModels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListRequest)
+func _MCPService_ExportBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MCPServiceServer).ExportBatch(m, &mCPServiceExportBatchServer{stream})
+}
+
+type MCPService_ExportBatchServer interface {
+	Send(*Execution) error
+	grpc.ServerStream
+}
+
+type mCPServiceExportBatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *mCPServiceExportBatchServer) Send(m *Execution) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MCPService_PurgeCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeCacheRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPServiceServer).ListModels(ctx, in)
+		return srv.(MCPServiceServer).PurgeCache(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPService_ListModels_FullMethodName,
+		FullMethod: MCPService_PurgeCache_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPServiceServer).ListModels(ctx, req.(*ListRequest))
+		return srv.(MCPServiceServer).PurgeCache(ctx, req.(*PurgeCacheRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPService_CreateContext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Context)
+func _MCPService_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogLevelRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPServiceServer).CreateContext(ctx, in)
+		return srv.(MCPServiceServer).SetLogLevel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPService_CreateContext_FullMethodName,
+		FullMethod: MCPService_SetLogLevel_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPServiceServer).CreateContext(ctx, req.(*Context))
+		return srv.(MCPServiceServer).SetLogLevel(ctx, req.(*LogLevelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPService_GetContext_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ContextRequest)
+func _MCPService_CreateSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Schedule)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPServiceServer).GetContext(ctx, in)
+		return srv.(MCPServiceServer).CreateSchedule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPService_GetContext_FullMethodName,
+		FullMethod: MCPService_CreateSchedule_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPServiceServer).GetContext(ctx, req.(*ContextRequest))
+		return srv.(MCPServiceServer).CreateSchedule(ctx, req.(*Schedule))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPService_ListContexts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _MCPService_ListSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPServiceServer).ListContexts(ctx, in)
+		return srv.(MCPServiceServer).ListSchedules(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPService_ListContexts_FullMethodName,
+		FullMethod: MCPService_ListSchedules_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPServiceServer).ListContexts(ctx, req.(*ListRequest))
+		return srv.(MCPServiceServer).ListSchedules(ctx, req.(*ListRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPService_ExecuteProtocol_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Protocol)
+func _MCPService_PauseSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseScheduleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPServiceServer).ExecuteProtocol(ctx, in)
+		return srv.(MCPServiceServer).PauseSchedule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPService_ExecuteProtocol_FullMethodName,
+		FullMethod: MCPService_PauseSchedule_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPServiceServer).ExecuteProtocol(ctx, req.(*Protocol))
+		return srv.(MCPServiceServer).PauseSchedule(ctx, req.(*PauseScheduleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _MCPService_GetProtocolStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProtocolRequest)
+func _MCPService_DeleteSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MCPServiceServer).GetProtocolStatus(ctx, in)
+		return srv.(MCPServiceServer).DeleteSchedule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: MCPService_GetProtocolStatus_FullMethodName,
+		FullMethod: MCPService_DeleteSchedule_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MCPServiceServer).GetProtocolStatus(ctx, req.(*ProtocolRequest))
+		return srv.(MCPServiceServer).DeleteSchedule(ctx, req.(*ScheduleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -463,6 +1690,81 @@ func _MCPService_DeleteData_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MCPService_PutSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Secret)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).PutSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_PutSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).PutSecret(ctx, req.(*Secret))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_ListSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).ListSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_ListSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).ListSecrets(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_DeleteSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MCPServiceServer).DeleteSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MCPService_DeleteSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MCPServiceServer).DeleteSecret(ctx, req.(*SecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MCPService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MCPServiceServer).Subscribe(m, &mCPServiceSubscribeServer{stream})
+}
+
+type MCPService_SubscribeServer interface {
+	Send(*ChangeEvent) error
+	grpc.ServerStream
+}
+
+type mCPServiceSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *mCPServiceSubscribeServer) Send(m *ChangeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // MCPService_ServiceDesc is the grpc.ServiceDesc for MCPService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -482,6 +1784,34 @@ var MCPService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListModels",
 			Handler:    _MCPService_ListModels_Handler,
 		},
+		{
+			MethodName: "GetModelHealth",
+			Handler:    _MCPService_GetModelHealth_Handler,
+		},
+		{
+			MethodName: "CreateModelVersion",
+			Handler:    _MCPService_CreateModelVersion_Handler,
+		},
+		{
+			MethodName: "GetModelSchema",
+			Handler:    _MCPService_GetModelSchema_Handler,
+		},
+		{
+			MethodName: "ListModelVersions",
+			Handler:    _MCPService_ListModelVersions_Handler,
+		},
+		{
+			MethodName: "SetModelAlias",
+			Handler:    _MCPService_SetModelAlias_Handler,
+		},
+		{
+			MethodName: "ListModelAliases",
+			Handler:    _MCPService_ListModelAliases_Handler,
+		},
+		{
+			MethodName: "DeleteModelAlias",
+			Handler:    _MCPService_DeleteModelAlias_Handler,
+		},
 		{
 			MethodName: "CreateContext",
 			Handler:    _MCPService_CreateContext_Handler,
@@ -494,6 +1824,38 @@ var MCPService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListContexts",
 			Handler:    _MCPService_ListContexts_Handler,
 		},
+		{
+			MethodName: "LinkContextModel",
+			Handler:    _MCPService_LinkContextModel_Handler,
+		},
+		{
+			MethodName: "UnlinkContextModel",
+			Handler:    _MCPService_UnlinkContextModel_Handler,
+		},
+		{
+			MethodName: "ListContextsForModel",
+			Handler:    _MCPService_ListContextsForModel_Handler,
+		},
+		{
+			MethodName: "CreateProtocol",
+			Handler:    _MCPService_CreateProtocol_Handler,
+		},
+		{
+			MethodName: "GetProtocol",
+			Handler:    _MCPService_GetProtocol_Handler,
+		},
+		{
+			MethodName: "ListProtocols",
+			Handler:    _MCPService_ListProtocols_Handler,
+		},
+		{
+			MethodName: "UpdateProtocol",
+			Handler:    _MCPService_UpdateProtocol_Handler,
+		},
+		{
+			MethodName: "DeleteProtocol",
+			Handler:    _MCPService_DeleteProtocol_Handler,
+		},
 		{
 			MethodName: "ExecuteProtocol",
 			Handler:    _MCPService_ExecuteProtocol_Handler,
@@ -502,6 +1864,62 @@ var MCPService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetProtocolStatus",
 			Handler:    _MCPService_GetProtocolStatus_Handler,
 		},
+		{
+			MethodName: "CancelExecution",
+			Handler:    _MCPService_CancelExecution_Handler,
+		},
+		{
+			MethodName: "ListExecutions",
+			Handler:    _MCPService_ListExecutions_Handler,
+		},
+		{
+			MethodName: "GetExecution",
+			Handler:    _MCPService_GetExecution_Handler,
+		},
+		{
+			MethodName: "CountTokens",
+			Handler:    _MCPService_CountTokens_Handler,
+		},
+		{
+			MethodName: "GetQueueStats",
+			Handler:    _MCPService_GetQueueStats_Handler,
+		},
+		{
+			MethodName: "GetUsageReport",
+			Handler:    _MCPService_GetUsageReport_Handler,
+		},
+		{
+			MethodName: "ExecuteBatch",
+			Handler:    _MCPService_ExecuteBatch_Handler,
+		},
+		{
+			MethodName: "GetBatchStatus",
+			Handler:    _MCPService_GetBatchStatus_Handler,
+		},
+		{
+			MethodName: "PurgeCache",
+			Handler:    _MCPService_PurgeCache_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _MCPService_SetLogLevel_Handler,
+		},
+		{
+			MethodName: "CreateSchedule",
+			Handler:    _MCPService_CreateSchedule_Handler,
+		},
+		{
+			MethodName: "ListSchedules",
+			Handler:    _MCPService_ListSchedules_Handler,
+		},
+		{
+			MethodName: "PauseSchedule",
+			Handler:    _MCPService_PauseSchedule_Handler,
+		},
+		{
+			MethodName: "DeleteSchedule",
+			Handler:    _MCPService_DeleteSchedule_Handler,
+		},
 		{
 			MethodName: "AddData",
 			Handler:    _MCPService_AddData_Handler,
@@ -518,7 +1936,40 @@ var MCPService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteData",
 			Handler:    _MCPService_DeleteData_Handler,
 		},
+		{
+			MethodName: "PutSecret",
+			Handler:    _MCPService_PutSecret_Handler,
+		},
+		{
+			MethodName: "ListSecrets",
+			Handler:    _MCPService_ListSecrets_Handler,
+		},
+		{
+			MethodName: "DeleteSecret",
+			Handler:    _MCPService_DeleteSecret_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamExecution",
+			Handler:       _MCPService_StreamExecution_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchExecution",
+			Handler:       _MCPService_WatchExecution_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportBatch",
+			Handler:       _MCPService_ExportBatch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Subscribe",
+			Handler:       _MCPService_Subscribe_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/proto/mcp.proto",
 }